@@ -0,0 +1,188 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AdminLvl - JSON view of a level as served by AdminHandler.
+type AdminLvl struct {
+	Level    string   `json:"level"`
+	Ignore   bool     `json:"ignore"`
+	Flags    int      `json:"flags"`
+	PkgFlags int      `json:"pkgflags"`
+	Prefix   string   `json:"prefix"`
+	Output   string   `json:"output"`
+	Stats    LvlStats `json:"stats"`
+}
+
+// AdminGroup - JSON view of a group as served by AdminHandler.
+type AdminGroup struct {
+	Name      string     `json:"name"`
+	Label     string     `json:"label"`
+	IgnoreAll bool       `json:"ignoreall"`
+	MinLevel  string     `json:"minlevel"`
+	Async     bool       `json:"async"`
+	Levels    []AdminLvl `json:"levels"`
+}
+
+// AdminReq - JSON body of an AdminHandler PUT or POST. Group selects a
+// group by Name, else Label selects all groups with that label. When
+// Level is set Ignore, Flags and PkgFlags apply to that level only else
+// to all levels of the group. Nil fields are left unchanged.
+type AdminReq struct {
+	Group     string `json:"group"`
+	Label     string `json:"label"`
+	Level     string `json:"level"`
+	Ignore    *bool  `json:"ignore"`
+	IgnoreAll *bool  `json:"ignoreall"`
+	Flags     *int   `json:"flags"`
+	PkgFlags  *int   `json:"pkgflags"`
+	MinLevel  string `json:"minlevel"`
+}
+
+// adminView - returns JSON view of g.
+func adminView(g *GlvlStruct) AdminGroup {
+	a := AdminGroup{
+		Name:      g.Name,
+		Label:     g.Label(),
+		IgnoreAll: g.GetIgnoreAll(),
+		MinLevel:  g.MinLevel().String(),
+		Async:     g.Async(),
+	}
+	for v := LevelTrace; v <= LevelEmergency; v++ {
+		l := g.Lvl(v)
+		a.Levels = append(a.Levels, AdminLvl{
+			Level:    v.String(),
+			Ignore:   l.Ignore(),
+			Flags:    l.Flags(),
+			PkgFlags: l.PkgFlags(),
+			Prefix:   l.Prefix(),
+			Output:   fmt.Sprintf("%T", l.GetOutput()),
+			Stats:    l.Stats(),
+		})
+	}
+	return a
+}
+
+// adminApply - applies r to g.
+func adminApply(g *GlvlStruct, r *AdminReq) error {
+	var l *LvlStruct
+	if r.Level != "" {
+		v, err := ParseLevel(r.Level)
+		if err != nil {
+			return err
+		}
+		l = g.Lvl(v)
+	}
+	var minLevel Level
+	if r.MinLevel != "" {
+		v, err := ParseLevel(r.MinLevel)
+		if err != nil {
+			return err
+		}
+		minLevel = v
+	}
+
+	if r.Ignore != nil {
+		if l != nil {
+			l.SetIgnore(*r.Ignore)
+		} else {
+			g.SetIgnore(*r.Ignore)
+		}
+	}
+	if r.Flags != nil {
+		if l != nil {
+			l.SetFlags(*r.Flags)
+		} else {
+			g.SetFlags(*r.Flags)
+		}
+	}
+	if r.PkgFlags != nil {
+		if l != nil {
+			l.SetPkgFlags(*r.PkgFlags)
+		} else {
+			g.SetPkgFlags(*r.PkgFlags)
+		}
+	}
+	if r.IgnoreAll != nil {
+		g.SetIgnoreAll(*r.IgnoreAll)
+	}
+	if r.MinLevel != "" {
+		g.SetMinLevel(minLevel)
+	}
+	return nil
+}
+
+// AdminHandler - returns an http.Handler to inspect and change registered
+// groups at runtime. GET lists all groups [or one with ?group=Name] as JSON,
+// PUT or POST applies a JSON AdminReq and responds with the changed groups.
+func AdminHandler() http.Handler {
+	return http.HandlerFunc(adminServe)
+}
+
+func adminServe(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		var list []AdminGroup
+		name := req.URL.Query().Get("group")
+		for _, g := range Groups() {
+			if name == "" || g.Name == name {
+				list = append(list, adminView(g))
+			}
+		}
+		if name != "" && len(list) == 0 {
+			http.Error(w, "grplog: unknown group "+name, http.StatusNotFound)
+			return
+		}
+		adminJSON(w, list)
+
+	case http.MethodPut, http.MethodPost:
+		var r AdminReq
+		if err := json.NewDecoder(req.Body).Decode(&r); err != nil {
+			http.Error(w, "grplog: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		var groups []*GlvlStruct
+		switch {
+		case r.Group != "":
+			if g := Lookup(r.Group); g != nil {
+				groups = append(groups, g)
+			}
+		case r.Label != "":
+			groups = LookupLabel(r.Label)
+		default:
+			http.Error(w, "grplog: group or label required", http.StatusBadRequest)
+			return
+		}
+		if len(groups) == 0 {
+			http.Error(w, "grplog: no such group", http.StatusNotFound)
+			return
+		}
+		var list []AdminGroup
+		for _, g := range groups {
+			if err := adminApply(g, &r); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			list = append(list, adminView(g))
+		}
+		adminJSON(w, list)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "grplog: method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func adminJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
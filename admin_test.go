@@ -0,0 +1,86 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func adminDo(t *testing.T, method, target, body string) (int, []grplog.AdminGroup) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	grplog.AdminHandler().ServeHTTP(rec, req)
+	var list []grplog.AdminGroup
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+			t.Fatalf("%s %s json err:%v body:%s", method, target, err, rec.Body)
+		}
+	}
+	return rec.Code, list
+}
+
+func TestAdminHandler(t *testing.T) {
+	g := grplog.MustNew("adminlog:", 0)
+	defer g.Close()
+	g.SetOutput(ioutil.Discard)
+	g.Info.Println("x")
+
+	code, list := adminDo(t, http.MethodGet, "/?group="+url.QueryEscape(g.Name), "")
+	if code != http.StatusOK || len(list) != 1 {
+		t.Fatalf("GET got code:%d groups:%d", code, len(list))
+	}
+	a := list[0]
+	if a.Name != g.Name || a.Label != "adminlog:" || a.MinLevel != "Trace" || len(a.Levels) != 9 {
+		t.Errorf("GET got:%+v", a)
+	}
+	if li := a.Levels[grplog.LevelInfo]; li.Level != "Info" || li.Prefix != "adminlog:INFO: " ||
+		!strings.HasSuffix(li.Output, ".discard") || li.Stats.Msgs != 1 {
+		t.Errorf("GET Info level got:%+v", li)
+	}
+
+	code, list = adminDo(t, http.MethodPut, "/",
+		`{"group":"`+g.Name+`","level":"debug","ignore":true,"pkgflags":2}`)
+	if code != http.StatusOK || len(list) != 1 {
+		t.Fatalf("PUT got code:%d", code)
+	}
+	if !g.Debug.Ignore() || g.Debug.PkgFlags() != 2 || g.Info.Ignore() {
+		t.Errorf("PUT level not applied debug ignore:%t pkgflags:%d", g.Debug.Ignore(), g.Debug.PkgFlags())
+	}
+
+	code, _ = adminDo(t, http.MethodPost, "/", `{"label":"adminlog","minlevel":"Warning","flags":0,"ignoreall":true}`)
+	if code != http.StatusOK {
+		t.Fatalf("POST got code:%d", code)
+	}
+	if g.MinLevel() != grplog.LevelWarning || g.Error.Flags() != 0 || !g.GetIgnoreAll() {
+		t.Errorf("POST group not applied minlevel:%v flags:%d ignoreall:%t", g.MinLevel(), g.Error.Flags(), g.GetIgnoreAll())
+	}
+
+	tests := []struct {
+		method, body string
+		code         int
+	}{
+		{http.MethodPut, `{"group":"nosuch"}`, http.StatusNotFound},
+		{http.MethodPut, `{}`, http.StatusBadRequest},
+		{http.MethodPut, `{`, http.StatusBadRequest},
+		{http.MethodPut, `{"group":"` + g.Name + `","level":"verbose"}`, http.StatusBadRequest},
+		{http.MethodDelete, ``, http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		if code, _ := adminDo(t, test.method, "/", test.body); code != test.code {
+			t.Errorf("%s %s got code:%d want:%d", test.method, test.body, code, test.code)
+		}
+	}
+	if code, _ := adminDo(t, http.MethodGet, "/?group=nosuch", ""); code != http.StatusNotFound {
+		t.Errorf("GET unknown group got code:%d want:%d", code, http.StatusNotFound)
+	}
+}
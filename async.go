@@ -0,0 +1,313 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"io"
+	"sync"
+)
+
+// OverflowPolicy - what an async group does when its queue is full.
+type OverflowPolicy int
+
+// async group queue overflow policies
+const (
+	OverflowBlock      OverflowPolicy = iota // caller waits for room in queue without group lock held
+	OverflowDropNewest                       // message being logged is dropped
+	OverflowDropOldest                       // oldest queued message is dropped
+)
+
+// AsyncQlenDef - default async queue length
+const AsyncQlenDef = 1024
+
+type asyncItem struct {
+	l *LvlStruct // level that queued item
+	w io.Writer
+	b []byte
+	r *Record // if non nil w is a RecordWriter
+}
+
+// asyncStruct - bounded queue of formatted messages drained by a
+// background writer goroutine.
+type asyncStruct struct {
+	q       chan asyncItem
+	policy  OverflowPolicy
+	mu      sync.Mutex
+	cond    *sync.Cond // signaled when pending reaches zero
+	pending int        // messages queued or being written
+	dropped uint64     // messages dropped due to overflow
+	done    chan struct{}
+	stash   []asyncItem // OverflowBlock items awaiting send, guarded by group lock
+	fails   []asyncFail // failed writes awaiting handling by a caller
+}
+
+// asyncFail - a failed async write.
+type asyncFail struct {
+	it  asyncItem
+	err error
+}
+
+func newAsync(qlen int, policy OverflowPolicy) *asyncStruct {
+	if qlen <= 0 {
+		qlen = AsyncQlenDef
+	}
+	a := &asyncStruct{
+		q:      make(chan asyncItem, qlen),
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+	a.cond = sync.NewCond(&a.mu)
+	go a.writer()
+	return a
+}
+
+// writer - background goroutine writing queued messages until q is closed.
+func (a *asyncStruct) writer() {
+	defer close(a.done)
+	for it := range a.q {
+		var err error
+		if it.r != nil {
+			err = it.w.(RecordWriter).WriteRecord(it.r)
+		} else {
+			_, err = it.w.Write(it.b)
+		}
+		if err != nil {
+			// handled by a caller, see handleFails, as writer must
+			// never take group lock
+			a.mu.Lock()
+			a.fails = append(a.fails, asyncFail{it, err})
+			a.mu.Unlock()
+		}
+		a.release(1)
+	}
+}
+
+func (a *asyncStruct) release(n int) {
+	a.mu.Lock()
+	a.pending -= n
+	if a.pending == 0 {
+		a.cond.Broadcast()
+	}
+	a.mu.Unlock()
+}
+
+// enqueue - queues it honoring overflow policy. It is called with group
+// lock held so under OverflowBlock an item finding the queue full [or
+// behind one that did] is stashed for the caller to send once the lock
+// is released, see send, rather than blocking every logger of the group.
+func (a *asyncStruct) enqueue(it asyncItem) {
+	a.mu.Lock()
+	a.pending++
+	a.mu.Unlock()
+	if a.policy == OverflowBlock {
+		if len(a.stash) == 0 {
+			select {
+			case a.q <- it:
+				return
+			default:
+			}
+		}
+		a.stash = append(a.stash, it)
+		return
+	}
+	for {
+		select {
+		case a.q <- it:
+			return
+		default:
+		}
+		if a.policy == OverflowDropNewest {
+			a.drop(1)
+			return
+		}
+		// OverflowDropOldest
+		select {
+		case <-a.q:
+			a.drop(1)
+		default:
+		}
+	}
+}
+
+// takeStash - returns async group, if any, and items stashed by enqueue
+// clearing the stash. Must be called with group lock held.
+func (g *GlvlStruct) takeStash() (*asyncStruct, []asyncItem) {
+	if g == nil || g.async == nil {
+		return nil, nil
+	}
+	a := g.async
+	stash := a.stash
+	a.stash = nil
+	return a, stash
+}
+
+// send - queues stashed items waiting for room as needed.
+// Must be called without group lock held.
+func (a *asyncStruct) send(stash []asyncItem) {
+	for _, it := range stash {
+		a.q <- it
+	}
+}
+
+// handleFails - handles failed writes per level error policy.
+// Must be called without group lock held.
+func (a *asyncStruct) handleFails() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	fails := a.fails
+	a.fails = nil
+	a.mu.Unlock()
+	for _, f := range fails {
+		f.it.l.asyncErr(f.err, f.it)
+	}
+}
+
+func (a *asyncStruct) drop(n int) {
+	a.mu.Lock()
+	a.dropped += uint64(n)
+	a.mu.Unlock()
+	a.release(n)
+}
+
+// flush - waits until all queued messages have been written.
+func (a *asyncStruct) flush() {
+	a.mu.Lock()
+	for a.pending > 0 {
+		a.cond.Wait()
+	}
+	a.mu.Unlock()
+}
+
+// close - flushes then stops the background writer.
+func (a *asyncStruct) close() {
+	a.flush()
+	close(a.q)
+	<-a.done
+}
+
+// asyncWriter - io.Writer handed to log.logger which queues messages
+// for the real io.Writer w.
+type asyncWriter struct {
+	a *asyncStruct
+	l *LvlStruct
+	w io.Writer
+}
+
+func (aw asyncWriter) Write(p []byte) (int, error) {
+	aw.a.enqueue(asyncItem{l: aw.l, w: aw.w, b: append([]byte(nil), p...)})
+	return len(p), nil
+}
+
+// asyncErr - counts and handles per level error policy a failed async
+// write, ErrPanic is handled as ErrCallback. Must be called without lock held.
+func (l *LvlStruct) asyncErr(err error, it asyncItem) {
+	l.mu.Lock()
+	l.errCtr++
+	l.mu.Unlock()
+	line := string(it.b)
+	if it.r != nil {
+		line = l.Prefix() + it.r.Msg
+	}
+	l.errh(err, line, true)
+}
+
+// writer - returns io.Writer messages are written to, which is logOutput
+// or when parent group is async a writer that queues for logOutput.
+func (l *LvlStruct) writer() io.Writer {
+	if l.par != nil && l.par.async != nil {
+		return asyncWriter{a: l.par.async, l: l, w: l.logOutput}
+	}
+	return l.logOutput
+}
+
+// SetAsync - switches group into async mode where messages are formatted
+// on the calling goroutine and queued [up to qlen] for a background
+// goroutine to write, policy decides what happens when queue is full.
+// qlen <= 0 uses AsyncQlenDef. Use Flush to wait for queued messages
+// and Close to return to synchronous writes, Fatal and Panic funcs flush
+// the queue before exiting or panicking. Failed writes are counted
+// and handled per level error policy by the next caller logging to the
+// group [or Flush], an ErrPanic policy is handled as ErrCallback since
+// the writer goroutine cannot panic on a caller's behalf.
+func (g *GlvlStruct) SetAsync(qlen int, policy OverflowPolicy) {
+	g.stopAsync()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.async = newAsync(qlen, policy)
+	for _, v := range g.lvlList() {
+		(*v.level).log.SetOutput((*v.level).writer())
+	}
+}
+
+// Async - returns true if group is in async mode.
+func (g *GlvlStruct) Async() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.async != nil
+}
+
+// AsyncDropped - returns number of messages dropped due to async queue overflow.
+func (g *GlvlStruct) AsyncDropped() uint64 {
+	g.mu.Lock()
+	a := g.async
+	g.mu.Unlock()
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.dropped
+}
+
+// Flush - outputs pending dedup repeat counts [see SetDedup] and waits
+// until all messages queued in async mode are written.
+func (g *GlvlStruct) Flush() {
+	g.mu.Lock()
+	g.dedupFlushll()
+	a := g.async
+	g.unlock()
+	if a != nil {
+		a.flush()
+		a.handleFails()
+	}
+}
+
+// flushAsync - flushes parent group as Flush does.
+// Must be called without lock held.
+func (l *LvlStruct) flushAsync() {
+	if l.par != nil {
+		l.par.Flush()
+	}
+}
+
+// Close - outputs pending dedup repeat counts, flushes and stops async
+// mode background writer and removes group from the registry of groups
+// [see Groups], subsequent messages are written synchronously.
+func (g *GlvlStruct) Close() error {
+	g.mu.Lock()
+	g.dedupFlushll()
+	g.unlock()
+	g.stopAsync()
+	Unregister(g)
+	return nil
+}
+
+func (g *GlvlStruct) stopAsync() {
+	g.mu.Lock()
+	a := g.async
+	g.async = nil
+	if a != nil {
+		for _, v := range g.lvlList() {
+			(*v.level).log.SetOutput((*v.level).writer())
+		}
+	}
+	g.mu.Unlock()
+	if a != nil {
+		a.close()
+		a.handleFails()
+	}
+}
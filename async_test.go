@@ -0,0 +1,180 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+// gateWriter - io.Writer whose writes wait until gate is closed.
+type gateWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gateWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncBlock(t *testing.T) {
+	w := &gateWriter{gate: make(chan struct{})}
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(w)
+	g.SetAsync(2, grplog.OverflowBlock)
+	if !g.Async() {
+		t.Fatalf("[%s].Async() got:false want:true", g.Name)
+	}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			g.Info.Println(i)
+		}
+		close(done)
+	}()
+	close(w.gate)
+	<-done
+	g.Flush()
+	want := "glog:INFO: 0\nglog:INFO: 1\nglog:INFO: 2\nglog:INFO: 3\nglog:INFO: 4\n"
+	if got := w.String(); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+	if err := g.Close(); err != nil {
+		t.Error(err)
+	}
+	if g.Async() {
+		t.Errorf("[%s].Async() after Close got:true want:false", g.Name)
+	}
+	g.Info.Println("sync")
+	if got := w.String(); got != want+"glog:INFO: sync\n" {
+		t.Errorf("after Close got:%q", got)
+	}
+}
+
+func TestAsyncDrop(t *testing.T) {
+	for _, policy := range []grplog.OverflowPolicy{grplog.OverflowDropNewest, grplog.OverflowDropOldest} {
+		w := &gateWriter{gate: make(chan struct{})}
+		g := grplog.MustNew("glog:", 0)
+		g.SetFlags(0)
+		g.SetOutput(w)
+		g.SetAsync(2, policy)
+		for i := 0; i < 10; i++ {
+			g.Info.Println(i)
+		}
+		close(w.gate)
+		g.Flush()
+		got := w.String()
+		written := bytes.Count([]byte(got), []byte("\n"))
+		if dropped := g.AsyncDropped(); int(dropped)+written != 10 || dropped == 0 {
+			t.Errorf("policy:%d dropped:%d written:%d want sum 10", policy, dropped, written)
+		}
+		last := fmt.Sprintf("glog:INFO: %d\n", 9)
+		if policy == grplog.OverflowDropOldest && !bytes.HasSuffix([]byte(got), []byte(last)) {
+			t.Errorf("policy:%d got:%q want suffix %q", policy, got, last)
+		}
+		_ = g.Close()
+	}
+}
+
+func TestAsyncBlockNoGroupStall(t *testing.T) {
+	w := &gateWriter{gate: make(chan struct{})}
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(w)
+	g.SetAsync(1, grplog.OverflowBlock)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			g.Info.Println(i)
+		}
+		close(done)
+	}()
+
+	// while the logger waits on the full queue the group stays usable
+	other := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		g.Error.SetIgnore(true)
+		_ = g.Stats()
+		close(other)
+	}()
+	select {
+	case <-other:
+	case <-time.After(5 * time.Second):
+		t.Fatal("group lock held while waiting on full async queue")
+	}
+	close(w.gate)
+	<-done
+	g.Flush()
+	want := "glog:INFO: 0\nglog:INFO: 1\nglog:INFO: 2\nglog:INFO: 3\nglog:INFO: 4\n"
+	if got := w.String(); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+	_ = g.Close()
+}
+
+func TestAsyncWriteErrors(t *testing.T) {
+	for _, policy := range []grplog.ErrPolicy{grplog.ErrCallback, grplog.ErrPanic} {
+		g := grplog.MustNew("aelog:", 0)
+		g.SetFlags(0)
+		g.SetOutput(errWriter{})
+		var buf syncBuffer
+		g.Debug.SetOutput(&buf)
+		g.SetErrPolicy(policy)
+		var mu sync.Mutex
+		calls := 0
+		g.SetErrorHandler(func(l *grplog.LvlStruct, err error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			// handler may log to the same async group
+			g.Debug.Println("write failed:", err)
+		})
+		g.SetAsync(2, grplog.OverflowBlock)
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < 50; i++ {
+				g.Info.Println(i)
+			}
+			g.Flush()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("policy:%d async logging to failing writer deadlocked", policy)
+		}
+		if s := g.Info.Stats(); s.WriteErrs != 50 {
+			t.Errorf("policy:%d WriteErrs got:%d want:50", policy, s.WriteErrs)
+		}
+		mu.Lock()
+		if calls != 50 {
+			t.Errorf("policy:%d handler calls got:%d want:50", policy, calls)
+		}
+		mu.Unlock()
+		_ = g.Close()
+		if n := strings.Count(buf.String(), "aelog:DEBUG: write failed"); n != 50 {
+			t.Errorf("policy:%d handler logged %d lines want:50", policy, n)
+		}
+	}
+}
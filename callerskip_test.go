@@ -0,0 +1,62 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+var skipLog *grplog.LvlWithStruct
+
+func logHelper(msg string) {
+	skipLog.Println(msg)
+}
+
+func outputHelper(l *grplog.LvlStruct, msg string) {
+	_ = l.Output(2, msg)
+}
+
+func TestCallerSkip(t *testing.T) {
+	g := grplog.MustNew("cslog:", grplog.FfnBase)
+	defer g.Close()
+	g.SetFlags(log.Lshortfile)
+	var buf bytes.Buffer
+	g.SetOutput(&buf)
+
+	skipLog = g.Info.WithCallerSkip(1).With("k", 1)
+	logHelper("helped")
+	if got := buf.String(); !strings.HasPrefix(got, "cslog:INFO: callerskip_test.go:34") ||
+		!strings.HasSuffix(got, "FN:grplog_test.TestCallerSkip() helped k=1\n") {
+		t.Errorf("WithCallerSkip got:%q", got)
+	}
+
+	buf.Reset()
+	skipLog = g.Info.With("k", 2).WithCallerSkip(0)
+	logHelper("direct")
+	if got := buf.String(); !strings.Contains(got, "callerskip_test.go:19") || !strings.Contains(got, "FN:grplog_test.logHelper() direct k=2") {
+		t.Errorf("WithCallerSkip(0) got:%q", got)
+	}
+
+	buf.Reset()
+	outputHelper(g.Warning, "out")
+	if got := buf.String(); !strings.Contains(got, "callerskip_test.go:48") || !strings.Contains(got, "FN:grplog_test.TestCallerSkip() out") {
+		t.Errorf("Output(2) got:%q", got)
+	}
+
+	buf.Reset()
+	if err := g.Warning.Output(1, "one"); err != nil || !strings.Contains(buf.String(), "callerskip_test.go:54") {
+		t.Errorf("Output(1) got:%q err:%v", buf.String(), err)
+	}
+	g.Warning.SetIgnore(true)
+	buf.Reset()
+	if err := g.Warning.Output(1, "ignored"); err != nil || buf.Len() != 0 {
+		t.Errorf("ignored Output got:%q err:%v", buf.String(), err)
+	}
+}
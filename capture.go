@@ -0,0 +1,98 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// lineWriter - io.WriteCloser re-emitting each line written to it
+// through a given level.
+type lineWriter struct {
+	l   *LvlStruct
+	mu  sync.Mutex
+	buf []byte // partial line
+}
+
+// Writer - returns an io.WriteCloser which outputs each line written to
+// it via level l, i.e. for exec.Cmd Stdout or another logger's output.
+// Lines carry no file or func decoration since the call site is unknown.
+// Close outputs any final partial line.
+func (l *LvlStruct) Writer() io.WriteCloser {
+	return &lineWriter{l: l}
+}
+
+// Writer - returns an io.WriteCloser which outputs each line written to
+// it via group level v, see LvlStruct Writer. Returns nil if v is not valid.
+func (g *GlvlStruct) Writer(v Level) io.WriteCloser {
+	l := g.Lvl(v)
+	if l == nil {
+		return nil
+	}
+	return l.Writer()
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.l.outLine(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close - outputs any final partial line.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.l.outLine(string(w.buf))
+		w.buf = nil
+	}
+	return nil
+}
+
+// outLine - outputs s without call site decoration.
+func (l *LvlStruct) outLine(s string) {
+	if l.anyIgnore(true) {
+		return
+	}
+	l.mu.Lock()
+	err := l.outc(callerStruct{}, nil, s)
+	l.unlock()
+	if err != nil {
+		l.outErr(err, s)
+	}
+}
+
+// CaptureStdLog - redirects stdlib log package output to group level v,
+// stdlib log flags are cleared since level adds its own. Returns a func
+// which restores stdlib log output and flags.
+func (g *GlvlStruct) CaptureStdLog(v Level) (restore func()) {
+	w := g.Writer(v)
+	if w == nil {
+		return func() {}
+	}
+	orgw, orgflags := log.Writer(), log.Flags()
+	log.SetOutput(w)
+	log.SetFlags(0)
+	return func() {
+		log.SetOutput(orgw)
+		log.SetFlags(orgflags)
+		_ = w.Close()
+	}
+}
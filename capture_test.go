@@ -0,0 +1,59 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestCaptureStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	g := grplog.MustNew("glog:", grplog.FlagsDef)
+	g.SetFlags(log.Lshortfile)
+	g.SetOutput(&buf)
+
+	restore := g.CaptureStdLog(grplog.LevelWarning)
+	log.Println("from stdlib")
+	log.Printf("two\nlines")
+	restore()
+	if got := log.Flags(); got != log.LstdFlags {
+		t.Errorf("stdlib log flags not restored got:%d want:%d", got, log.LstdFlags)
+	}
+
+	want := "glog:WARNING: from stdlib\nglog:WARNING: two\nglog:WARNING: lines\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(&buf)
+
+	w := g.Writer(grplog.LevelInfo)
+	fmt.Fprint(w, "par")
+	fmt.Fprint(w, "tial\r\nnext\n")
+	fmt.Fprint(w, "end")
+	g.Info.SetIgnore(true)
+	fmt.Fprint(w, "\nignored\n")
+	g.Info.SetIgnore(false)
+	fmt.Fprint(w, "last")
+	_ = w.Close()
+
+	want := "glog:INFO: partial\nglog:INFO: next\nglog:INFO: last\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+	if g.Writer(grplog.Level(99)) != nil {
+		t.Errorf("Writer(99) should be nil")
+	}
+}
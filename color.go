@@ -0,0 +1,111 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode - whether a level colors its prefix with ANSI escapes.
+type ColorMode int
+
+// color modes
+const (
+	ColorOff  ColorMode = iota // never color [default]
+	ColorAuto                  // color if output is a terminal [tty or console] and NO_COLOR is unset
+	ColorOn                    // always color
+)
+
+// Palette - ANSI SGR escape sequence per level used to color the prefix.
+type Palette [LevelEmergency + 1]string
+
+// ColorReset - ANSI escape sequence ending a colored prefix.
+const ColorReset = "\x1b[0m"
+
+// DefaultPalette - colors of levels without their own, see SetColorCode.
+var DefaultPalette = Palette{
+	LevelTrace:     "\x1b[90m",   // gray
+	LevelDebug:     "\x1b[36m",   // cyan
+	LevelInfo:      "\x1b[32m",   // green
+	LevelNotice:    "\x1b[34m",   // blue
+	LevelWarning:   "\x1b[33m",   // yellow
+	LevelAlert:     "\x1b[35m",   // magenta
+	LevelError:     "\x1b[31m",   // red
+	LevelCritical:  "\x1b[1;31m", // bold red
+	LevelEmergency: "\x1b[7;31m", // inverse red
+}
+
+// isTerminal - returns true if w is a terminal [not merely a character
+// device such as /dev/null], see isTerminalFile.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isTerminalFile(f)
+}
+
+// colorll - resolves whether level output is colored per its mode and
+// output. Must be called with lock held whenever either changes.
+func (l *LvlStruct) colorll() {
+	switch l.color {
+	case ColorOn:
+		l.colorOn = true
+	case ColorAuto:
+		l.colorOn = os.Getenv("NO_COLOR") == "" && isTerminal(l.logOutput)
+	default:
+		l.colorOn = false
+	}
+}
+
+// colorPrefix - returns prefix p wrapped in level color escapes.
+func (l *LvlStruct) colorPrefix(p string) string {
+	code := l.colorCode
+	if code == "" && l.lvl >= LevelTrace && l.lvl <= LevelEmergency {
+		code = DefaultPalette[l.lvl]
+	}
+	return code + p + ColorReset
+}
+
+// Color - returns level color mode.
+func (l *LvlStruct) Color() ColorMode {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.color
+}
+
+// SetColor - sets level color mode, ColorAuto checks the output when set
+// and on SetOutput.
+func (l *LvlStruct) SetColor(m ColorMode) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.color = m
+	l.colorll()
+}
+
+// SetColorCode - sets ANSI escape sequence coloring level prefix
+// i.e. "\x1b[31m", empty uses DefaultPalette.
+func (l *LvlStruct) SetColorCode(code string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.colorCode = code
+}
+
+// SetColor - sets color mode of all group log levels.
+func (g *GlvlStruct) SetColor(m ColorMode) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).color = m
+		(*v.level).colorll()
+	}
+}
+
+// SetPalette - sets color code of each group log level per p.
+func (g *GlvlStruct) SetPalette(p Palette) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).colorCode = p[v.lvl]
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestColor(t *testing.T) {
+	g := grplog.MustNew("clog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf bytes.Buffer
+	g.SetOutput(&buf)
+
+	g.SetColor(grplog.ColorOn)
+	g.Error.Println("red")
+	g.Info.SetColorCode("\x1b[1m")
+	g.Info.Println("bold")
+	want := "\x1b[31mclog:ERROR: \x1b[0mred\n\x1b[1mclog:INFO: \x1b[0mbold\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+	if g.Error.Prefix() != "clog:ERROR: " {
+		t.Errorf("Prefix got:%q", g.Error.Prefix())
+	}
+
+	var p grplog.Palette
+	p[grplog.LevelWarning] = "\x1b[4m"
+	g.SetPalette(p)
+	buf.Reset()
+	g.Warning.Println("w")
+	g.Info.Println("i")
+	want = "\x1b[4mclog:WARNING: \x1b[0mw\n\x1b[32mclog:INFO: \x1b[0mi\n"
+	if got := buf.String(); got != want {
+		t.Errorf("palette got:%q want:%q", got, want)
+	}
+
+	// auto only colors terminals
+	g.SetColor(grplog.ColorAuto)
+	buf.Reset()
+	g.Info.Println("plain")
+	if got := buf.String(); got != "clog:INFO: plain\n" {
+		t.Errorf("auto to buffer got:%q", got)
+	}
+	f, err := ioutil.TempFile("", "grplogcolor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	g.Info.SetOutput(f)
+	g.Info.Println("plain")
+	if b, _ := ioutil.ReadFile(f.Name()); string(b) != "clog:INFO: plain\n" {
+		t.Errorf("auto to file got:%q", b)
+	}
+	if g.Info.Color() != grplog.ColorAuto {
+		t.Errorf("Color got:%v", g.Info.Color())
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConfigStruct - declarative configuration of groups, see LoadConfig.
+type ConfigStruct struct {
+	Groups []GroupConfig `json:"groups"`
+}
+
+// GroupConfig - configuration of the groups with Label, a group is
+// created via NewSpecial if none is registered. Empty or nil fields
+// are left unchanged [or defaulted on creation]. Level names in maps
+// are case insensitive i.e. "error". An output is "stdout", "stderr",
+// "discard" or a file path which is opened for append.
+type GroupConfig struct {
+	Label     string            `json:"label"`
+	MinLevel  string            `json:"minlevel"`  // see SetMinLevel
+	Flags     string            `json:"flags"`     // stdlib log flags, see ParseEnv
+	PkgFlags  string            `json:"pkgflags"`  // see ParseEnv
+	AlignFile *int              `json:"alignfile"` // see SetAlignFile
+	AlignFunc *int              `json:"alignfunc"` // see SetAlignFunc
+	Output    string            `json:"output"`    // output of all levels
+	Outputs   map[string]string `json:"outputs"`   // output per level, overrides Output
+	Prefixes  map[string]string `json:"prefixes"`  // prefix per level, see LvlStruct SetPrefix
+}
+
+// files opened for config outputs, kept open across reloads while referenced
+// by a registered group, see cfgRelease
+var cfgFiles = struct {
+	sync.Mutex
+	m     map[string]*os.File
+	stale []*os.File // replaced handles, closed once unreferenced
+}{m: make(map[string]*os.File)}
+
+// serializes Apply so cfgRelease never closes a file opened but not yet applied
+var cfgApply sync.Mutex
+
+// WatchIntervalDef - WatchConfig poll interval used when none is given.
+const WatchIntervalDef = time.Second
+
+// cfgOutput - returns io.Writer for config output name, a file moved or
+// removed since it was opened [i.e. by logrotate] is reopened.
+func cfgOutput(name string) (io.Writer, error) {
+	switch strings.ToLower(name) {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	case "discard":
+		return ioutil.Discard, nil
+	}
+	cfgFiles.Lock()
+	defer cfgFiles.Unlock()
+	if f, ok := cfgFiles.m[name]; ok {
+		fi, err1 := os.Stat(name)
+		ofi, err2 := f.Stat()
+		if err1 == nil && err2 == nil && os.SameFile(fi, ofi) {
+			return f, nil
+		}
+		cfgFiles.stale = append(cfgFiles.stale, f)
+		delete(cfgFiles.m, name)
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	cfgFiles.m[name] = f
+	return f, nil
+}
+
+// cfgRelease - closes config opened files no longer the output of any
+// level of a registered group, async groups are flushed first so queued
+// messages reach the files before closing.
+func cfgRelease() {
+	inuse := make(map[io.Writer]bool)
+	for _, g := range Groups() {
+		g.Flush()
+		for v := LevelTrace; v <= LevelEmergency; v++ {
+			inuse[g.Lvl(v).GetOutput()] = true
+		}
+	}
+	cfgFiles.Lock()
+	defer cfgFiles.Unlock()
+	for name, f := range cfgFiles.m {
+		if !inuse[f] {
+			_ = f.Close()
+			delete(cfgFiles.m, name)
+		}
+	}
+	var stale []*os.File
+	for _, f := range cfgFiles.stale {
+		if inuse[f] {
+			stale = append(stale, f)
+		} else {
+			_ = f.Close()
+		}
+	}
+	cfgFiles.stale = stale
+}
+
+// ParseConfig - parses JSON configuration b.
+func ParseConfig(b []byte) (*ConfigStruct, error) {
+	c := &ConfigStruct{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, errors.New("grplog: config: " + err.Error())
+	}
+	return c, nil
+}
+
+// LoadConfig - reads and parses JSON configuration file path i.e.
+//
+//	{"groups": [
+//	  {"label": "glog:", "minlevel": "info", "flags": "DTS", "pkgflags": "base+nogps",
+//	   "output": "stdout", "outputs": {"error": "/var/log/app.err", "trace": "discard"}}
+//	]}
+func LoadConfig(path string) (*ConfigStruct, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(b)
+}
+
+// cfgGroup - a GroupConfig resolved and validated ready to apply.
+type cfgGroup struct {
+	gc       *GroupConfig
+	minLevel *Level
+	flags    *int
+	pkgFlags *int
+	outputs  [LevelEmergency + 1]io.Writer // nil if unchanged
+	prefixes [LevelEmergency + 1]*string   // nil if unchanged
+}
+
+func cfgLevel(name string) (Level, error) {
+	v, err := ParseLevel(name)
+	if err != nil {
+		return v, errors.New("grplog: config: unknown level " + strconv.Quote(name))
+	}
+	return v, nil
+}
+
+func (gc *GroupConfig) resolve() (*cfgGroup, error) {
+	if gc.Label == "" {
+		return nil, errors.New("grplog: config: group missing label")
+	}
+	r := &cfgGroup{gc: gc}
+	if gc.MinLevel != "" {
+		v, err := cfgLevel(gc.MinLevel)
+		if err != nil {
+			return nil, err
+		}
+		r.minLevel = &v
+	}
+	if gc.Flags != "" {
+		v, err := parseLflags(gc.Flags)
+		if err != nil {
+			return nil, err
+		}
+		r.flags = &v
+	}
+	if gc.PkgFlags != "" {
+		v, err := parsePkgFlags(gc.PkgFlags)
+		if err != nil {
+			return nil, err
+		}
+		r.pkgFlags = &v
+	}
+	if gc.Output != "" {
+		w, err := cfgOutput(gc.Output)
+		if err != nil {
+			return nil, err
+		}
+		for i := range r.outputs {
+			r.outputs[i] = w
+		}
+	}
+	for name, out := range gc.Outputs {
+		v, err := cfgLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		w, err := cfgOutput(out)
+		if err != nil {
+			return nil, err
+		}
+		r.outputs[v] = w
+	}
+	for name, prefix := range gc.Prefixes {
+		v, err := cfgLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		p := prefix
+		r.prefixes[v] = &p
+	}
+	return r, nil
+}
+
+// create - returns a new group via NewSpecial per r.
+func (r *cfgGroup) create() (*GlvlStruct, error) {
+	g := &GlvlStruct{firstIowr: IowrDefault()}
+	for _, v := range g.lvlList() {
+		if w := r.outputs[v.lvl]; w != nil {
+			*v.iowr = w
+		}
+	}
+	flags, lflags := FlagsDef, LflagsDef
+	if r.pkgFlags != nil {
+		flags = *r.pkgFlags
+	}
+	if r.flags != nil {
+		lflags = *r.flags
+	}
+	return NewSpecial(r.gc.Label, flags, lflags, g.firstIowr)
+}
+
+// apply - applies r to existing group g.
+func (r *cfgGroup) apply(g *GlvlStruct) {
+	if r.minLevel != nil {
+		g.SetMinLevel(*r.minLevel)
+	}
+	if r.flags != nil {
+		g.SetFlags(*r.flags)
+	}
+	if r.pkgFlags != nil {
+		g.SetPkgFlags(*r.pkgFlags)
+	}
+	if r.gc.AlignFile != nil {
+		g.SetAlignFile(*r.gc.AlignFile)
+	}
+	if r.gc.AlignFunc != nil {
+		g.SetAlignFunc(*r.gc.AlignFunc)
+	}
+	for v := LevelTrace; v <= LevelEmergency; v++ {
+		l := g.Lvl(v)
+		if w := r.outputs[v]; w != nil && w != l.GetOutput() {
+			l.SetOutput(w)
+		}
+		if p := r.prefixes[v]; p != nil {
+			l.SetPrefix(*p)
+		}
+	}
+}
+
+// Apply - applies c to registered groups with matching labels, creating
+// groups which do not exist. Nothing is applied if c has any error.
+// Returns the configured groups in c order. Output files reopened as moved
+// or no longer used by any registered group are closed afterwards.
+func (c *ConfigStruct) Apply() ([]*GlvlStruct, error) {
+	cfgApply.Lock()
+	defer cfgApply.Unlock()
+	defer cfgRelease()
+	var list []*cfgGroup
+	for i := range c.Groups {
+		r, err := c.Groups[i].resolve()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	var groups []*GlvlStruct
+	for _, r := range list {
+		found := LookupLabel(r.gc.Label)
+		if len(found) == 0 {
+			g, err := r.create()
+			if err != nil {
+				return groups, err
+			}
+			found = append(found, g)
+		}
+		for _, g := range found {
+			r.apply(g)
+		}
+		groups = append(groups, found...)
+	}
+	return groups, nil
+}
+
+// WatchConfig - loads and applies configuration file path, then polls its
+// modification time every interval [WatchIntervalDef if <= 0] re-applying
+// it when changed. Errors are passed to errf if not nil. Call the returned
+// stop func to end watching.
+func WatchConfig(path string, interval time.Duration, errf func(error)) (stop func()) {
+	if interval <= 0 {
+		interval = WatchIntervalDef
+	}
+	report := func(err error) {
+		if err != nil && errf != nil {
+			errf(err)
+		}
+	}
+	load := func() {
+		c, err := LoadConfig(path)
+		if err == nil {
+			_, err = c.Apply()
+		}
+		report(err)
+	}
+
+	var mod time.Time
+	var size int64
+	if fi, err := os.Stat(path); err == nil {
+		mod, size = fi.ModTime(), fi.Size()
+	}
+	load()
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick.C:
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				report(err)
+				continue
+			}
+			if !fi.ModTime().Equal(mod) || fi.Size() != size {
+				mod, size = fi.ModTime(), fi.Size()
+				load()
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestConfigApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogcfg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	errfile := filepath.Join(dir, "err.log")
+
+	c, err := grplog.ParseConfig([]byte(`{"groups": [
+		{"label": "cfgalog:", "minlevel": "notice", "flags": "off", "pkgflags": "base",
+		 "output": "discard", "outputs": {"Error": "` + errfile + `"},
+		 "prefixes": {"error": "cfgalog:E: "}, "alignfile": 20}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := c.Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups want 1", len(groups))
+	}
+	g := groups[0]
+	defer g.Close()
+	if g.MinLevel() != grplog.LevelNotice || g.Info.Flags() != grplog.LflagsOff || g.Info.PkgFlags() != grplog.FfnBase {
+		t.Errorf("got minlevel:%v flags:%d pkgflags:%d", g.MinLevel(), g.Info.Flags(), g.Info.PkgFlags())
+	}
+	if g.Info.GetOutput() != ioutil.Discard || g.Error.Prefix() != "cfgalog:E: " {
+		t.Errorf("got info output:%T error prefix:%q", g.Info.GetOutput(), g.Error.Prefix())
+	}
+	g.Error.Print("oops")
+	b, _ := ioutil.ReadFile(errfile)
+	if !strings.HasPrefix(string(b), "cfgalog:E: ") || !strings.HasSuffix(string(b), "oops\n") {
+		t.Errorf("error file got:%q", b)
+	}
+
+	// existing group is reconfigured not recreated
+	c.Groups[0].MinLevel = "debug"
+	groups2, err := c.Apply()
+	if err != nil || len(groups2) != 1 || groups2[0] != g || g.MinLevel() != grplog.LevelDebug {
+		t.Errorf("reapply got groups:%v err:%v minlevel:%v", groups2, err, g.MinLevel())
+	}
+
+	// nothing applied on error
+	for _, bad := range []string{
+		`{"groups": [{"label": "cfgalog:", "minlevel": "info"}, {"minlevel": "info"}]}`,
+		`{"groups": [{"label": "cfgalog:", "minlevel": "info", "outputs": {"verbose": "stdout"}}]}`,
+		`{"groups": [{"label": "cfgalog:", "minlevel": "info", "flags": "XYZ"}]}`,
+		`{"groups": [{"label": "cfgalog:", "minlevel": "info", "output": "` + filepath.Join(dir, "no", "such") + `"}]}`,
+	} {
+		c, err := grplog.ParseConfig([]byte(bad))
+		if err == nil {
+			_, err = c.Apply()
+		}
+		if err == nil {
+			t.Errorf("config %s should have failed", bad)
+		}
+	}
+	if g.MinLevel() != grplog.LevelDebug {
+		t.Errorf("bad config was partially applied")
+	}
+	if _, err := grplog.ParseConfig([]byte(`{"groups": [`)); err == nil {
+		t.Errorf("ParseConfig of bad json should have failed")
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogcfg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "grplog.json")
+	write := func(lvl string) {
+		cfg := `{"groups": [{"label": "cfgwlog:", "minlevel": "` + lvl + `", "output": "discard"}]}`
+		if err := ioutil.WriteFile(path, []byte(cfg), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("info")
+
+	errc := make(chan error, 10)
+	stop := grplog.WatchConfig(path, 5*time.Millisecond, func(err error) { errc <- err })
+	defer stop()
+	g := grplog.LookupLabel("cfgwlog")
+	if len(g) != 1 || g[0].MinLevel() != grplog.LevelInfo {
+		t.Fatalf("initial load got groups:%v", g)
+	}
+	defer g[0].Close()
+
+	write("critical")
+	// ensure mtime changes on filesystems with coarse timestamps
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(path, future, future)
+	deadline := time.Now().Add(2 * time.Second)
+	for g[0].MinLevel() != grplog.LevelCritical && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if g[0].MinLevel() != grplog.LevelCritical {
+		t.Errorf("reload got minlevel:%v want Critical", g[0].MinLevel())
+	}
+	select {
+	case err := <-errc:
+		t.Errorf("unexpected watch error:%v", err)
+	default:
+	}
+	stop()
+}
+
+func TestConfigReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogcfg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logfile := filepath.Join(dir, "app.log")
+
+	c, err := grplog.ParseConfig([]byte(`{"groups": [
+		{"label": "cfgrlog:", "flags": "off", "pkgflags": "off", "output": "` + logfile + `"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := c.Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := groups[0]
+	defer g.Close()
+	g.Info.Print("one")
+
+	// as logrotate would
+	if err := os.Rename(logfile, logfile+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	g.Info.Print("two")
+	if b, _ := ioutil.ReadFile(logfile + ".1"); string(b) != "cfgrlog:INFO: one\n" {
+		t.Errorf("moved file got:%q", b)
+	}
+	if b, _ := ioutil.ReadFile(logfile); string(b) != "cfgrlog:INFO: two\n" {
+		t.Errorf("reopened file got:%q", b)
+	}
+
+	// file no longer referenced is closed
+	f := g.Info.GetOutput().(*os.File)
+	c.Groups[0].Output = "discard"
+	if _, err := c.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Errorf("unreferenced config file should be closed")
+	}
+}
+
+func TestWatchConfigInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogcfg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "grplog.json")
+	if err := ioutil.WriteFile(path, []byte(`{"groups": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// must not panic
+	stop := grplog.WatchConfig(path, 0, nil)
+	stop()
+}
@@ -0,0 +1,187 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type ctxKeyType int
+
+const (
+	ctxGroupKey ctxKeyType = iota
+	ctxFieldsKey
+)
+
+// registered context keys output by PrintCtx variants
+var ctxKeys = struct {
+	sync.Mutex
+	names []string
+	keys  []interface{}
+}{}
+
+// RegisterCtxKey - registers context key whose value, when present in
+// the context passed to PrintCtx variants, is output as field name=value
+// i.e. RegisterCtxKey("req", reqIDKey). Registering an existing name
+// replaces its key.
+func RegisterCtxKey(name string, key interface{}) {
+	ctxKeys.Lock()
+	defer ctxKeys.Unlock()
+	for i := range ctxKeys.names {
+		if ctxKeys.names[i] == name {
+			ctxKeys.keys[i] = key
+			return
+		}
+	}
+	ctxKeys.names = append(ctxKeys.names, name)
+	ctxKeys.keys = append(ctxKeys.keys, key)
+}
+
+// UnregisterCtxKey - removes context key registered under name.
+func UnregisterCtxKey(name string) {
+	ctxKeys.Lock()
+	defer ctxKeys.Unlock()
+	for i := range ctxKeys.names {
+		if ctxKeys.names[i] == name {
+			ctxKeys.names = append(ctxKeys.names[:i], ctxKeys.names[i+1:]...)
+			ctxKeys.keys = append(ctxKeys.keys[:i], ctxKeys.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+var defGroup struct {
+	sync.Mutex
+	g *GlvlStruct
+}
+
+// DefaultGroup - returns package default group used by FromContext when
+// context carries none, created on first use as MustNew("glog:", FlagsDef).
+func DefaultGroup() *GlvlStruct {
+	defGroup.Lock()
+	defer defGroup.Unlock()
+	if defGroup.g == nil {
+		defGroup.g = MustNew("glog:", FlagsDef)
+	}
+	return defGroup.g
+}
+
+// SetDefaultGroup - sets package default group, see DefaultGroup.
+func SetDefaultGroup(g *GlvlStruct) {
+	defGroup.Lock()
+	defer defGroup.Unlock()
+	defGroup.g = g
+}
+
+// NewContext - returns a copy of ctx carrying group g.
+func NewContext(ctx context.Context, g *GlvlStruct) context.Context {
+	return context.WithValue(ctx, ctxGroupKey, g)
+}
+
+// FromContext - returns group carried by ctx or DefaultGroup if none.
+func FromContext(ctx context.Context) *GlvlStruct {
+	if ctx != nil {
+		if g, ok := ctx.Value(ctxGroupKey).(*GlvlStruct); ok && g != nil {
+			return g
+		}
+	}
+	return DefaultGroup()
+}
+
+// ContextWith - returns a copy of ctx carrying key/value fields kv
+// in addition to any it already carries, output by PrintCtx variants.
+func ContextWith(ctx context.Context, kv ...interface{}) context.Context {
+	old, _ := ctx.Value(ctxFieldsKey).([]interface{})
+	return context.WithValue(ctx, ctxFieldsKey, joinFields(old, kv))
+}
+
+// ctxFields - returns registered context key values present in ctx,
+// then fields carried by ctx, then kv.
+func ctxFields(ctx context.Context, kv []interface{}) []interface{} {
+	if ctx == nil {
+		return kv
+	}
+	var f []interface{}
+	ctxKeys.Lock()
+	for i, key := range ctxKeys.keys {
+		if v := ctx.Value(key); v != nil {
+			f = append(f, ctxKeys.names[i], v)
+		}
+	}
+	ctxKeys.Unlock()
+	if cf, ok := ctx.Value(ctxFieldsKey).([]interface{}); ok {
+		f = append(f, cf...)
+	}
+	if len(f) == 0 {
+		return kv
+	}
+	return append(f, kv...)
+}
+
+// PrintCtx - Print plus context fields, see RegisterCtxKey and ContextWith.
+func (l *LvlStruct) PrintCtx(ctx context.Context, x ...interface{}) {
+	if l.anyIgnore(true) {
+		return
+	}
+	_ = l.out(ctxFields(ctx, nil), fmt.Sprint(x...))
+}
+
+// PrintfCtx - Printf plus context fields.
+func (l *LvlStruct) PrintfCtx(ctx context.Context, f string, x ...interface{}) {
+	if l.anyIgnore(true) {
+		return
+	}
+	_ = l.out(ctxFields(ctx, nil), fmt.Sprintf(f, x...))
+}
+
+// PrintlnCtx - Println plus context fields.
+func (l *LvlStruct) PrintlnCtx(ctx context.Context, x ...interface{}) {
+	if l.anyIgnore(true) {
+		return
+	}
+	_ = l.out(ctxFields(ctx, nil), fmt.Sprintln(x...))
+}
+
+// PrintwCtx - Printw plus context fields which precede kv.
+func (l *LvlStruct) PrintwCtx(ctx context.Context, msg string, kv ...interface{}) {
+	if l.anyIgnore(true) {
+		return
+	}
+	_ = l.out(ctxFields(ctx, kv), msg)
+}
+
+// PrintCtx - LvlWithStruct Print plus context fields which follow bound fields.
+func (w *LvlWithStruct) PrintCtx(ctx context.Context, x ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(joinFields(w.kv, ctxFields(ctx, nil)), fmt.Sprint(x...))
+}
+
+// PrintfCtx - LvlWithStruct Printf plus context fields.
+func (w *LvlWithStruct) PrintfCtx(ctx context.Context, f string, x ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(joinFields(w.kv, ctxFields(ctx, nil)), fmt.Sprintf(f, x...))
+}
+
+// PrintlnCtx - LvlWithStruct Println plus context fields.
+func (w *LvlWithStruct) PrintlnCtx(ctx context.Context, x ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(joinFields(w.kv, ctxFields(ctx, nil)), fmt.Sprintln(x...))
+}
+
+// PrintwCtx - LvlWithStruct Printw plus context fields.
+func (w *LvlWithStruct) PrintwCtx(ctx context.Context, msg string, kv ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(joinFields(w.kv, ctxFields(ctx, kv)), msg)
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+type ctxKey string
+
+func TestContext(t *testing.T) {
+	var buf bytes.Buffer
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(&buf)
+
+	if got := grplog.FromContext(context.Background()); got != grplog.DefaultGroup() {
+		t.Errorf("FromContext without group got:%v want DefaultGroup", got.Name)
+	}
+	ctx := grplog.NewContext(context.Background(), g)
+	if got := grplog.FromContext(ctx); got != g {
+		t.Errorf("FromContext got:%s want:%s", got.Name, g.Name)
+	}
+
+	grplog.RegisterCtxKey("req", ctxKey("reqid"))
+	grplog.RegisterCtxKey("trace", ctxKey("traceid"))
+	defer grplog.UnregisterCtxKey("req")
+	defer grplog.UnregisterCtxKey("trace")
+
+	ctx = context.WithValue(ctx, ctxKey("reqid"), "r1")
+	ctx = grplog.ContextWith(ctx, "user", 42)
+
+	l := grplog.FromContext(ctx)
+	l.Info.PrintlnCtx(ctx, "handled")
+	l.Info.PrintwCtx(ctx, "done", "ms", 3)
+	l.Info.With("svc", "a").PrintfCtx(ctx, "n=%d", 1)
+	l.Info.PrintCtx(context.Background(), "plain")
+
+	want := "glog:INFO: handled req=r1 user=42\n" +
+		"glog:INFO: done req=r1 user=42 ms=3\n" +
+		"glog:INFO: n=1 svc=a req=r1 user=42\n" +
+		"glog:INFO: plain\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:%q\nwant:%q", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"fmt"
+	"time"
+)
+
+// dupStruct - state of the current run of identical messages of a level.
+type dupStruct struct {
+	window time.Duration // max duration of a run
+	pc     uintptr       // call site of last message
+	msg    string        // text of last message including fields
+	beg    time.Time     // time last message was output, zero if no run
+	n      uint64        // repeats of last message not output
+	run    uint64        // incremented per new run
+	timer  *time.Timer   // flushes repeat count when window expires
+}
+
+// repeated - returns true if message is a repeat of the last message within
+// the dedup window, otherwise any repeat count is output and the message
+// starts a new run. Must be called with lock held.
+func (l *LvlStruct) repeated(c callerStruct, kv []interface{}, s string) bool {
+	d := l.dedup
+	msg := s
+	if len(kv) > 0 {
+		msg = appendFields(s, kv)
+	}
+	now := time.Now()
+	if !d.beg.IsZero() && c.pc == d.pc && msg == d.msg && now.Sub(d.beg) < d.window {
+		d.n++
+		if d.timer == nil {
+			run := d.run
+			d.timer = time.AfterFunc(d.window-now.Sub(d.beg), func() { l.dedupExpire(d, run) })
+		}
+		return true
+	}
+	// write error is counted in stats
+	_ = l.dedupFlush(d)
+	d.run++
+	d.pc, d.msg, d.beg = c.pc, msg, now
+	return false
+}
+
+// dedupFlush - outputs and clears repeat count of d. Must be called with lock held.
+func (l *LvlStruct) dedupFlush(d *dupStruct) error {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	n := d.n
+	d.n = 0
+	if n == 0 {
+		return nil
+	}
+	return l.outw(callerStruct{}, nil, fmt.Sprintf("last message repeated %d times", n))
+}
+
+// dedupExpire - ends run of d if it is still run when its window expires.
+func (l *LvlStruct) dedupExpire(d *dupStruct, run uint64) {
+	l.mu.Lock()
+	if l.dedup != d || d.run != run {
+		l.mu.Unlock()
+		return
+	}
+	d.timer = nil
+	n := d.n
+	err := l.dedupFlush(d)
+	// next message starts a new run even if identical
+	d.run++
+	d.beg = time.Time{}
+	l.unlock()
+	if err != nil {
+		l.outErr(err, fmt.Sprintf("last message repeated %d times", n))
+	}
+}
+
+// Dedup - returns level dedup window, 0 if dedup is off.
+func (l *LvlStruct) Dedup() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dedup == nil {
+		return 0
+	}
+	return l.dedup.window
+}
+
+// SetDedup - collapses consecutive identical messages [same text, fields
+// and call site] output within window of the first into one line
+// "last message repeated N times" output when the run ends, the window
+// expires or on group Flush, Close and Fatal/Panic funcs. A window <= 0
+// turns dedup off, outputting any pending count.
+func (l *LvlStruct) SetDedup(window time.Duration) {
+	l.mu.Lock()
+	defer l.unlock()
+	l.setDedupll(window)
+}
+
+func (l *LvlStruct) setDedupll(window time.Duration) {
+	if l.dedup != nil {
+		_ = l.dedupFlush(l.dedup)
+		l.dedup = nil
+	}
+	if window > 0 {
+		l.dedup = &dupStruct{window: window}
+	}
+}
+
+// SetDedup - same as LvlStruct SetDedup for all group log levels.
+func (g *GlvlStruct) SetDedup(window time.Duration) {
+	g.mu.Lock()
+	defer g.unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).setDedupll(window)
+	}
+}
+
+// dedupFlushll - outputs pending repeat counts of all group log levels.
+// Must be called with lock held.
+func (g *GlvlStruct) dedupFlushll() {
+	for _, v := range g.lvlList() {
+		if l := *v.level; l.dedup != nil {
+			// write error is counted in stats
+			_ = l.dedupFlush(l.dedup)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestDedup(t *testing.T) {
+	g := grplog.MustNew("dlog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf syncBuffer
+	g.SetOutput(&buf)
+	g.Info.SetDedup(time.Hour)
+	if g.Info.Dedup() != time.Hour || g.Debug.Dedup() != 0 {
+		t.Errorf("Dedup got Info:%v Debug:%v", g.Info.Dedup(), g.Debug.Dedup())
+	}
+
+	for i := 0; i < 4; i++ {
+		g.Info.Println("conn refused")
+	}
+	g.Info.Println("conn refused") // other call site
+	for i := 0; i < 2; i++ {
+		g.Info.With("try", 1).Println("conn refused")
+	}
+	g.Info.Println("done")
+	g.Info.SetDedup(0)
+
+	want := "dlog:INFO: conn refused\n" +
+		"dlog:INFO: last message repeated 3 times\n" +
+		"dlog:INFO: conn refused\n" +
+		"dlog:INFO: conn refused try=1\n" +
+		"dlog:INFO: last message repeated 1 times\n" +
+		"dlog:INFO: done\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	g := grplog.MustNew("dwlog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf syncBuffer
+	g.SetOutput(&buf)
+	g.SetDedup(20 * time.Millisecond)
+
+	say := func(n int) {
+		for i := 0; i < n; i++ {
+			g.Warning.Println("disk full")
+		}
+	}
+	say(3)
+	want := "dwlog:WARNING: disk full\ndwlog:WARNING: last message repeated 2 times\n"
+	deadline := time.Now().Add(2 * time.Second)
+	for buf.String() != want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("after window got:\n%s\nwant:\n%s", got, want)
+	}
+	say(1)
+	if got := buf.String(); !strings.HasSuffix(got, "times\ndwlog:WARNING: disk full\n") {
+		t.Errorf("message after window not output got:\n%s", got)
+	}
+}
+
+func TestDedupFlush(t *testing.T) {
+	g := grplog.MustNew("dflog:", 0)
+	g.SetFlags(0)
+	var buf syncBuffer
+	g.SetOutput(&buf)
+	g.SetAsync(8, grplog.OverflowBlock)
+	g.SetDedup(time.Hour)
+
+	want := "dflog:INFO: tick\ndflog:INFO: last message repeated 2 times\n"
+	for pass := 0; pass < 2; pass++ {
+		// single call site so all messages are repeats
+		for i := 0; i < 3-pass; i++ {
+			g.Info.Println("tick")
+		}
+		if pass == 0 {
+			g.Flush()
+			if got := buf.String(); got != want {
+				t.Errorf("after Flush got:%q want:%q", got, want)
+			}
+		}
+	}
+	_ = g.Close()
+	want += "dflog:INFO: last message repeated 2 times\n"
+	if got := buf.String(); got != want {
+		t.Errorf("after Close got:%q want:%q", got, want)
+	}
+}
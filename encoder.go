@@ -0,0 +1,191 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Record - a single log entry as handed to an Encoder.
+type Record struct {
+	Group  string        // group label less trailing ':' i.e. "glog"
+	Level  Level         //
+	Time   time.Time     // zero if log flags exclude date and time
+	File   string        // zero if log flags exclude filename
+	Line   int           //
+	Func   string        // empty if pkg flags exclude funcname
+	Msg    string        // message less trailing newline
+	Fields []interface{} // key/value pairs, see LvlStruct.With
+}
+
+// RecordWriter - may be implemented by a level io.Writer which wants each
+// Record rather than formatted bytes, i.e. SyslogStruct. When a level
+// io.Writer is a RecordWriter its Encoder is not used.
+type RecordWriter interface {
+	io.Writer
+	WriteRecord(r *Record) error
+}
+
+// Encoder - formats a Record into the bytes written to a level io.Writer.
+// An Encoder may be shared by many levels and groups so must be safe
+// for concurrent use.
+type Encoder interface {
+	Encode(r *Record) []byte
+}
+
+// JSONEncoder - encodes each Record as one JSON object per line i.e.
+// {"time":"...","group":"glog","level":"INFO","file":"x.go:12","fn":"main.run","msg":"..."}
+// followed by any key/value fields.
+type JSONEncoder struct{}
+
+// Encode - see Encoder.
+func (JSONEncoder) Encode(r *Record) []byte {
+	b := make([]byte, 0, 256)
+	b = append(b, '{')
+	if !r.Time.IsZero() {
+		b = jsonField(b, "time", r.Time.Format(time.RFC3339Nano))
+	}
+	b = jsonField(b, "group", r.Group)
+	b = jsonField(b, "level", levelLabel(r.Level))
+	if r.File != "" {
+		b = jsonField(b, "file", r.File+":"+strconv.Itoa(r.Line))
+	}
+	if r.Func != "" {
+		b = jsonField(b, "fn", r.Func)
+	}
+	b = jsonField(b, "msg", r.Msg)
+	for i := 0; i < len(r.Fields); i += 2 {
+		if i+1 == len(r.Fields) {
+			b = jsonField(b, "!BADKEY", r.Fields[i])
+			break
+		}
+		b = jsonField(b, fmt.Sprint(r.Fields[i]), r.Fields[i+1])
+	}
+	b[len(b)-1] = '}'
+	return append(b, '\n')
+}
+
+// jsonField - appends "key":value, to b.
+func jsonField(b []byte, key string, v interface{}) []byte {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	kb, _ := json.Marshal(key)
+	vb, err := json.Marshal(v)
+	if err != nil {
+		vb, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b = append(b, kb...)
+	b = append(b, ':')
+	b = append(b, vb...)
+	return append(b, ',')
+}
+
+// LogfmtEncoder - encodes each Record as one logfmt line i.e.
+// time=... group=glog level=INFO file=x.go:12 fn=main.run msg="..."
+// followed by any key/value fields.
+type LogfmtEncoder struct{}
+
+// Encode - see Encoder.
+func (LogfmtEncoder) Encode(r *Record) []byte {
+	b := make([]byte, 0, 256)
+	if !r.Time.IsZero() {
+		b = logfmtField(b, "time", r.Time.Format(time.RFC3339Nano))
+	}
+	b = logfmtField(b, "group", r.Group)
+	b = logfmtField(b, "level", levelLabel(r.Level))
+	if r.File != "" {
+		b = logfmtField(b, "file", r.File+":"+strconv.Itoa(r.Line))
+	}
+	if r.Func != "" {
+		b = logfmtField(b, "fn", r.Func)
+	}
+	b = logfmtField(b, "msg", r.Msg)
+	for i := 0; i < len(r.Fields); i += 2 {
+		if i+1 == len(r.Fields) {
+			b = logfmtField(b, "!BADKEY", fmt.Sprint(r.Fields[i]))
+			break
+		}
+		b = logfmtField(b, fmt.Sprint(r.Fields[i]), fmt.Sprint(r.Fields[i+1]))
+	}
+	b[len(b)-1] = '\n'
+	return b
+}
+
+// logfmtField - appends key=value and a trailing space to b, value is
+// quoted as needed and key has any space, '=' or '"' replaced by '_'.
+func logfmtField(b []byte, key string, v string) []byte {
+	if key == "" {
+		key = "_"
+	}
+	for _, r := range key {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		b = append(b, string(r)...)
+	}
+	b = append(b, '=')
+	b = append(b, quoteField(v)...)
+	return append(b, ' ')
+}
+
+// levelLabel - returns level label as used in base labels i.e. "INFO".
+func levelLabel(v Level) string {
+	return strings.ToUpper(v.String())
+}
+
+// group - returns group label less trailing ':' or name if no group.
+func (l *LvlStruct) group() string {
+	if l.par == nil {
+		return l.name
+	}
+	return strings.TrimSuffix(l.par.label, ":")
+}
+
+// record - returns a Record of message s with call site c and fields kv.
+func (l *LvlStruct) record(c callerStruct, kv []interface{}, s string) *Record {
+	r := &Record{
+		Group:  l.group(),
+		Level:  l.lvl,
+		File:   c.file,
+		Line:   c.line,
+		Func:   c.fname,
+		Msg:    strings.TrimSuffix(s, "\n"),
+		Fields: kv,
+	}
+	lflags := l.log.Flags()
+	if lflags&(log.Ldate|log.Ltime|log.Lmicroseconds) > 0 {
+		r.Time = time.Now()
+		if lflags&log.LUTC > 0 {
+			r.Time = r.Time.UTC()
+		}
+	}
+	return r
+}
+
+// outRec - a worker func that hands a Record to the level RecordWriter
+// or encodes it via l.enc and writes it, bypassing stdlib log.logger.
+func (l *LvlStruct) outRec(c callerStruct, kv []interface{}, s string) error {
+	r := l.record(c, kv, s)
+	if rw, ok := l.logOutput.(RecordWriter); ok {
+		l.outCharCtr += uint64(len(r.Msg))
+		if l.par != nil && l.par.async != nil {
+			l.par.async.enqueue(asyncItem{l: l, w: rw, r: r})
+			return l.outStat(nil)
+		}
+		return l.outStat(rw.WriteRecord(r))
+	}
+	b := l.enc.Encode(r)
+	l.outCharCtr += uint64(len(b))
+	_, err := l.writer().Write(b)
+	return l.outStat(err)
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	iowr := grplog.IowrStruct{Trace: &buf, Debug: &buf, Info: &buf, Notice: &buf,
+		Warning: &buf, Alert: &buf, Error: &buf, Critical: &buf, Emergency: &buf}
+	g, err := grplog.NewSpecialEnc("glog:", grplog.FfnBase, grplog.LflagsDTS, iowr, grplog.JSONEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Info.With("user", 42).Printw("login \"x\"", "err", errors.New("bad"))
+	g.Error.Println("oops")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines want 2: %q", len(lines), buf.String())
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+		t.Fatalf("json.Unmarshal(%q) err:%v", lines[0], err)
+	}
+	want := map[string]interface{}{
+		"group": "glog",
+		"level": "INFO",
+		"fn":    "grplog_test.TestJSONEncoder",
+		"msg":   `login "x"`,
+		"user":  float64(42),
+		"err":   "bad",
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("key:%q got:%v want:%v", k, m[k], v)
+		}
+	}
+	if f, _ := m["file"].(string); !strings.HasPrefix(f, "encoder_test.go:") {
+		t.Errorf("key:file got:%q want prefix encoder_test.go:", f)
+	}
+	if _, ok := m["time"]; !ok {
+		t.Errorf("key:time missing in %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], `{"time":`) || !strings.Contains(lines[1], `"level":"ERROR"`) {
+		t.Errorf("unexpected error line %q", lines[1])
+	}
+
+	buf.Reset()
+	g.SetFlags(grplog.LflagsOff)
+	g.SetPkgFlags(grplog.FlagsOff)
+	g.Notice.Println("plain")
+	if got, want := buf.String(), `{"group":"glog","level":"NOTICE","msg":"plain"}`+"\n"; got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+
+	buf.Reset()
+	g.SetEncoder(nil)
+	g.Notice.Println("classic")
+	if got, want := buf.String(), "glog:NOTICE: classic\n"; got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	g, err := grplog.NewSpecialEnc("glog:", grplog.FfnBase, grplog.LflagsOff, grplog.IowrDefault(), grplog.LogfmtEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetOutput(&buf)
+	g.Info.With("user", 42, "bad key", "a=b").Printw("say \"hi\"\n", "empty", "")
+	want := `group=glog level=INFO fn=grplog_test.TestLogfmtEncoder msg="say \"hi\"" user=42 bad_key="a=b" empty=""` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+
+	buf.Reset()
+	g.SetFlags(grplog.LflagsDTS)
+	g.Warning.Print("x")
+	got := buf.String()
+	if !strings.HasPrefix(got, "time=") || !strings.Contains(got, " level=WARNING file=encoder_test.go:") ||
+		!strings.HasSuffix(got, " msg=x\n") {
+		t.Errorf("unexpected line %q", got)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EnvVar - environment variable read by ApplyEnv and NewFromEnv.
+const EnvVar = "GRPLOG"
+
+// EnvEntry - configuration of the groups matching Label, nil fields are
+// left unchanged. See ParseEnv.
+type EnvEntry struct {
+	Label     string // group label less trailing ':', "*" matches all groups
+	MinLevel  *Level // see SetMinLevel
+	Flags     *int   // stdlib log flags, see SetFlags
+	PkgFlags  *int   // see SetPkgFlags
+	AlignFile *int   // see SetAlignFile
+	AlignFunc *int   // see SetAlignFunc
+}
+
+// lflagsNames - names accepted for stdlib log flags
+var lflagsNames = map[string]int{
+	"DTS":  LflagsDTS,
+	"DTL":  LflagsDTL,
+	"DTSM": LflagsDTSM,
+	"DTLM": LflagsDTLM,
+	"DEF":  LflagsDef,
+	"DEFL": LflagsDefL,
+	"OFF":  LflagsOff,
+}
+
+// pkgFlagsNames - names accepted for pkg flags
+var pkgFlagsNames = map[string]int{
+	"BASE":  FfnBase,
+	"FULL":  FfnFull,
+	"NOGPS": Ffilenogps,
+	"DEF":   FlagsDef,
+	"OFF":   FlagsOff,
+}
+
+// parseLflags - returns stdlib log flags named s [see lflagsNames] or
+// given as an integer.
+func parseLflags(s string) (int, error) {
+	if v, ok := lflagsNames[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseInt(s, 0, 0)
+	if err != nil {
+		return 0, errors.New("grplog: bad flags " + strconv.Quote(s))
+	}
+	return int(v), nil
+}
+
+// parsePkgFlags - returns pkg flags named in s joined by '+' or '|'
+// i.e. "base+nogps" [see pkgFlagsNames] or given as an integer.
+func parsePkgFlags(s string) (int, error) {
+	if v, err := strconv.ParseInt(s, 0, 0); err == nil {
+		return int(v), nil
+	}
+	f := 0
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == '+' || r == '|' }) {
+		v, ok := pkgFlagsNames[strings.ToUpper(name)]
+		if !ok {
+			return 0, errors.New("grplog: bad pkgflags " + strconv.Quote(s))
+		}
+		f |= v
+	}
+	return f, nil
+}
+
+// ParseEnv - parses a group logging spec such as
+//
+//	glog:debug;flags=DTSM,blog:warning;pkgflags=base+nogps;alignfile=30
+//
+// which is a comma separated list of entries, each entry being a group
+// label optionally followed by ':' and a minimum level, then any ';'
+// separated options applying to groups with that label. A label of "*"
+// matches all groups. Options are flags [DTS, DTL, DTSM, DTLM, DEF, DEFL,
+// OFF or a number], pkgflags [base, full, nogps, def, off joined by '+'
+// or a number], alignfile and alignfunc.
+func ParseEnv(spec string) ([]EnvEntry, error) {
+	var list []EnvEntry
+	for _, ent := range strings.Split(spec, ",") {
+		ent = strings.TrimSpace(ent)
+		if ent == "" {
+			continue
+		}
+		opts := strings.Split(ent, ";")
+		var e EnvEntry
+		sel := strings.TrimSpace(opts[0])
+		if i := strings.LastIndex(sel, ":"); i >= 0 {
+			if lvl := strings.TrimSpace(sel[i+1:]); lvl != "" {
+				v, err := ParseLevel(lvl)
+				if err != nil {
+					return nil, err
+				}
+				e.MinLevel = &v
+			}
+			sel = sel[:i]
+		}
+		if sel == "" {
+			return nil, errors.New("grplog: missing group label in " + strconv.Quote(ent))
+		}
+		e.Label = sel
+
+		for _, opt := range opts[1:] {
+			opt = strings.TrimSpace(opt)
+			if opt == "" {
+				continue
+			}
+			kv := strings.SplitN(opt, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.New("grplog: bad option " + strconv.Quote(opt))
+			}
+			key, val := strings.ToLower(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1])
+			var v int
+			var err error
+			switch key {
+			case "flags":
+				v, err = parseLflags(val)
+				e.Flags = &v
+			case "pkgflags":
+				v, err = parsePkgFlags(val)
+				e.PkgFlags = &v
+			case "alignfile":
+				v, err = strconv.Atoi(val)
+				e.AlignFile = &v
+			case "alignfunc":
+				v, err = strconv.Atoi(val)
+				e.AlignFunc = &v
+			default:
+				err = errors.New("grplog: unknown option " + strconv.Quote(key))
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		list = append(list, e)
+	}
+	return list, nil
+}
+
+// Match - returns true if e applies to group g.
+func (e *EnvEntry) Match(g *GlvlStruct) bool {
+	return e.Label == "*" || strings.TrimSuffix(g.Label(), ":") == strings.TrimSuffix(e.Label, ":")
+}
+
+// Apply - applies e to group g regardless of label.
+func (e *EnvEntry) Apply(g *GlvlStruct) {
+	if e.MinLevel != nil {
+		g.SetMinLevel(*e.MinLevel)
+	}
+	if e.Flags != nil {
+		g.SetFlags(*e.Flags)
+	}
+	if e.PkgFlags != nil {
+		g.SetPkgFlags(*e.PkgFlags)
+	}
+	if e.AlignFile != nil {
+		g.SetAlignFile(*e.AlignFile)
+	}
+	if e.AlignFunc != nil {
+		g.SetAlignFunc(*e.AlignFunc)
+	}
+}
+
+// applyEntries - applies entries matching g in order.
+func applyEntries(g *GlvlStruct, list []EnvEntry) {
+	for i := range list {
+		if list[i].Match(g) {
+			list[i].Apply(g)
+		}
+	}
+}
+
+// ApplyEnv - applies the spec in environment variable GRPLOG [see ParseEnv]
+// to all registered groups.
+func ApplyEnv() error {
+	list, err := ParseEnv(os.Getenv(EnvVar))
+	if err != nil {
+		return err
+	}
+	for _, g := range Groups() {
+		applyEntries(g, list)
+	}
+	return nil
+}
+
+// NewFromEnv - same as New followed by applying the entries of
+// environment variable GRPLOG [see ParseEnv] matching glabel.
+func NewFromEnv(glabel string, flags int) (*GlvlStruct, error) {
+	list, err := ParseEnv(os.Getenv(EnvVar))
+	if err != nil {
+		return nil, err
+	}
+	g, err := New(glabel, flags)
+	if err != nil {
+		return nil, err
+	}
+	applyEntries(g, list)
+	return g, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestParseEnv(t *testing.T) {
+	list, err := grplog.ParseEnv("glog:debug, blog:warning;flags=DTSM;pkgflags=base+nogps;alignfile=30;alignfunc=12,*;flags=0x3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("got %d entries want 3", len(list))
+	}
+	if e := list[0]; e.Label != "glog" || *e.MinLevel != grplog.LevelDebug || e.Flags != nil {
+		t.Errorf("entry 0 got:%+v", e)
+	}
+	e := list[1]
+	if e.Label != "blog" || *e.MinLevel != grplog.LevelWarning || *e.Flags != grplog.LflagsDTSM ||
+		*e.PkgFlags != grplog.FfnBase|grplog.Ffilenogps || *e.AlignFile != 30 || *e.AlignFunc != 12 {
+		t.Errorf("entry 1 got:%+v", e)
+	}
+	if e := list[2]; e.Label != "*" || e.MinLevel != nil || *e.Flags != 3 {
+		t.Errorf("entry 2 got:%+v", e)
+	}
+
+	for _, bad := range []string{"glog:verbose", "glog:debug;flags=XYZ", "glog;pkgflags=base+bad", ":debug", "glog;color=on", "glog;alignfile"} {
+		if _, err := grplog.ParseEnv(bad); err == nil {
+			t.Errorf("ParseEnv(%q) should have failed", bad)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	orgenv, had := os.LookupEnv(grplog.EnvVar)
+	defer func() {
+		if had {
+			os.Setenv(grplog.EnvVar, orgenv)
+		} else {
+			os.Unsetenv(grplog.EnvVar)
+		}
+	}()
+
+	a := grplog.MustNew("envalog:", 0)
+	defer a.Close()
+	os.Setenv(grplog.EnvVar, "envalog:error;flags=off,envblog:notice;pkgflags=full")
+	if err := grplog.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if a.MinLevel() != grplog.LevelError || a.Info.Flags() != grplog.LflagsOff {
+		t.Errorf("ApplyEnv got minlevel:%v flags:%d", a.MinLevel(), a.Info.Flags())
+	}
+
+	b, err := grplog.NewFromEnv("envblog:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if b.MinLevel() != grplog.LevelNotice || b.Trace.PkgFlags() != grplog.FfnFull || b.Trace.Flags() != grplog.LflagsDef {
+		t.Errorf("NewFromEnv got minlevel:%v pkgflags:%d flags:%d", b.MinLevel(), b.Trace.PkgFlags(), b.Trace.Flags())
+	}
+
+	os.Setenv(grplog.EnvVar, "envblog:bogus")
+	if _, err := grplog.NewFromEnv("envblog:", 0); err == nil {
+		t.Errorf("NewFromEnv with bad spec should have failed")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrPolicy - what a level does when writing a message fails.
+type ErrPolicy int
+
+// level write error policies
+const (
+	ErrIgnore   ErrPolicy = iota // error is discarded [default]
+	ErrPanic                     // panic with the error [as ErrCallback in async mode]
+	ErrFallback                  // message is written to level fallback io.Writer
+	ErrCallback                  // parent group ErrorHandler is called
+)
+
+// ErrorHandler - called with the level and error when writing a message
+// fails and level error policy is ErrCallback. It is called without any
+// grplog lock held so it may itself log.
+type ErrorHandler func(l *LvlStruct, err error)
+
+// ErrPolicy - returns level write error policy.
+func (l *LvlStruct) ErrPolicy() ErrPolicy {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.errPolicy
+}
+
+// SetErrPolicy - sets level write error policy.
+func (l *LvlStruct) SetErrPolicy(p ErrPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errPolicy = p
+}
+
+// SetErrFallback - sets io.Writer messages are written to when policy is
+// ErrFallback and writing to level output fails, nil means os.Stderr.
+func (l *LvlStruct) SetErrFallback(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fallback = w
+}
+
+// SetErrPolicy - sets write error policy for all group log levels.
+func (g *GlvlStruct) SetErrPolicy(p ErrPolicy) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).errPolicy = p
+	}
+}
+
+// ErrorHandler - returns group ErrorHandler.
+func (g *GlvlStruct) ErrorHandler() ErrorHandler {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.errHandler
+}
+
+// SetErrorHandler - sets group ErrorHandler called for levels with
+// error policy ErrCallback.
+func (g *GlvlStruct) SetErrorHandler(h ErrorHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errHandler = h
+}
+
+// outErr - handles write error err of message s per level error policy.
+func (l *LvlStruct) outErr(err error, s string) {
+	l.errll(err, l.Prefix()+s)
+}
+
+// errll - worker func for outErr, line is the formatted message.
+// Must be called without lock held.
+func (l *LvlStruct) errll(err error, line string) {
+	l.errh(err, line, false)
+}
+
+// errh - worker func for errll and asyncErr, if async ErrPanic is
+// handled as ErrCallback. Must be called without lock held.
+func (l *LvlStruct) errh(err error, line string, async bool) {
+	l.mu.Lock()
+	p, fb := l.errPolicy, l.fallback
+	if async && p == ErrPanic {
+		p = ErrCallback
+	}
+	var h ErrorHandler
+	if l.par != nil {
+		h = l.par.errHandler
+	}
+	l.mu.Unlock()
+
+	switch p {
+	case ErrPanic:
+		panic(err)
+	case ErrFallback:
+		if fb == nil {
+			fb = os.Stderr
+		}
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		_, _ = io.WriteString(fb, line)
+	case ErrCallback:
+		if h != nil {
+			h(l, err)
+		}
+	default:
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestErrPolicy(t *testing.T) {
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(errWriter{})
+
+	// ErrIgnore default
+	if got := g.Info.ErrPolicy(); got != grplog.ErrIgnore {
+		t.Errorf("default ErrPolicy got:%v want:%v", got, grplog.ErrIgnore)
+	}
+	g.Info.Println("ignored error")
+
+	// ErrFallback
+	var fb bytes.Buffer
+	g.Info.SetErrPolicy(grplog.ErrFallback)
+	g.Info.SetErrFallback(&fb)
+	g.Info.Print("to fallback")
+	if got, want := fb.String(), "glog:INFO: to fallback\n"; got != want {
+		t.Errorf("ErrFallback got:%q want:%q", got, want)
+	}
+
+	// ErrCallback, handler may itself log
+	var calls []string
+	var out bytes.Buffer
+	g.Debug.SetOutput(&out)
+	g.SetErrPolicy(grplog.ErrCallback)
+	g.SetErrorHandler(func(l *grplog.LvlStruct, err error) {
+		calls = append(calls, l.Level().String())
+		g.Debug.Println("handled", l.Level(), err)
+	})
+	g.SetMinLevel(grplog.LevelDebug)
+	g.Println("group")
+	if len(calls) != 7 || calls[0] != "Info" || calls[6] != "Emergency" {
+		t.Errorf("ErrCallback calls got:%v want [Info ... Emergency]", calls)
+	}
+	if got, want := out.String(), "glog:DEBUG: group\nglog:DEBUG: handled Info write failed\n"; !bytes.HasPrefix([]byte(got), []byte(want)) {
+		t.Errorf("handler output got:%q want prefix:%q", got, want)
+	}
+
+	// ErrPanic
+	g.Warning.SetErrPolicy(grplog.ErrPanic)
+	func() {
+		defer func() {
+			if p := recover(); p == nil {
+				t.Errorf("ErrPanic should have paniced")
+			}
+		}()
+		g.Warning.Println("panics")
+	}()
+	// lock must not be held after panic
+	g.Warning.SetErrPolicy(grplog.ErrIgnore)
+	g.Warning.Println("no panic")
+}
+
+func TestErrPolicyAsync(t *testing.T) {
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(errWriter{})
+	g.SetAsync(0, grplog.OverflowBlock)
+	var fb bytes.Buffer
+	g.Error.SetErrPolicy(grplog.ErrFallback)
+	g.Error.SetErrFallback(&fb)
+	g.Error.Println("async")
+	g.Flush()
+	if got, want := fb.String(), "glog:ERROR: async\n"; got != want {
+		t.Errorf("async ErrFallback got:%q want:%q", got, want)
+	}
+	if got := g.Error.Stats().WriteErrs; got != 1 {
+		t.Errorf("async WriteErrs got:%d want:1", got)
+	}
+	_ = g.Close()
+}
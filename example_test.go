@@ -216,3 +216,22 @@ func Example_printvarious() {
 	// glog:DEBUG: github.com/phcurtis/grplog/example_test.go:185 FN:grplog_test.Example_printvarious() <=longfile less gps
 
 }
+
+func Example_with() {
+	g, err := grplog.NewSpecial("glog:", grplog.FlagsOff, grplog.LflagsOff, grplog.IowrDefault())
+	if err != nil {
+		log.Panic(err)
+	}
+	g.Info.Printw("login", "user", 42, "req", "abc")
+	w := g.Info.With("user", 42)
+	w.Println("request")
+	w.With("path", "/a b").Printf("took %dms", 3)
+	w.Printw("logout", "reason", "")
+	g.Info.SetIgnore(true)
+	w.Println("should NOT see")
+	// Output:
+	// glog:INFO: login user=42 req=abc
+	// glog:INFO: request user=42
+	// glog:INFO: took 3ms user=42 path="/a b"
+	// glog:INFO: logout user=42 reason=""
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LvlWithStruct - a derived logger of a given LvlStruct which carries bound
+// key/value fields that are output after the message on each Print.
+// Ignore state, flags and outputs are those of the LvlStruct it was derived from.
+type LvlWithStruct struct {
+	l    *LvlStruct
+	kv   []interface{} // bound key/value fields
+	skip int           // extra frames skipped resolving call site
+}
+
+// With - returns a derived logger carrying bound key/value fields
+// i.e. g.Info.With("user", 42, "req", "abc").Println("login")
+// outputs ... FN:main.run() login user=42 req=abc
+func (l *LvlStruct) With(kv ...interface{}) *LvlWithStruct {
+	return &LvlWithStruct{l: l, kv: joinFields(nil, kv)}
+}
+
+// Printw - Print msg followed by key/value pairs kv.
+func (l *LvlStruct) Printw(msg string, kv ...interface{}) {
+	if l.anyIgnore(true) {
+		return
+	}
+	_ = l.out(kv, msg)
+}
+
+// With - returns a derived logger carrying both w's and kv key/value fields.
+func (w *LvlWithStruct) With(kv ...interface{}) *LvlWithStruct {
+	return &LvlWithStruct{l: w.l, kv: joinFields(w.kv, kv), skip: w.skip}
+}
+
+// WithCallerSkip - returns a derived logger whose call site [file:line and
+// FN:] is n frames further up the stack, for use by logging helpers
+// i.e. func logErr(x ...interface{}) { errLog.Print(x...) } with
+// errLog := g.Error.WithCallerSkip(1) reports the caller of logErr.
+func (l *LvlStruct) WithCallerSkip(n int) *LvlWithStruct {
+	return &LvlWithStruct{l: l, skip: n}
+}
+
+// WithCallerSkip - returns a derived logger with w's fields skipping
+// n frames in addition to those w skips.
+func (w *LvlWithStruct) WithCallerSkip(n int) *LvlWithStruct {
+	return &LvlWithStruct{l: w.l, kv: w.kv, skip: w.skip + n}
+}
+
+// out - outputs s with kv skipping w.skip extra frames.
+func (w *LvlWithStruct) out(kv []interface{}, s string) error {
+	return w.l.outd(1+w.skip, kv, s)
+}
+
+// Lvl - returns the LvlStruct w was derived from.
+func (w *LvlWithStruct) Lvl() *LvlStruct {
+	return w.l
+}
+
+// Print - LvlStruct Print plus bound fields.
+func (w *LvlWithStruct) Print(x ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(w.kv, fmt.Sprint(x...))
+}
+
+// Printf - LvlStruct Printf plus bound fields.
+func (w *LvlWithStruct) Printf(f string, x ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(w.kv, fmt.Sprintf(f, x...))
+}
+
+// Println - LvlStruct Println plus bound fields.
+func (w *LvlWithStruct) Println(x ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(w.kv, fmt.Sprintln(x...))
+}
+
+// Printw - LvlStruct Printw plus bound fields, kv follows bound fields.
+func (w *LvlWithStruct) Printw(msg string, kv ...interface{}) {
+	if w.l.anyIgnore(true) {
+		return
+	}
+	_ = w.out(joinFields(w.kv, kv), msg)
+}
+
+// CondPrint - conditional version of Print
+func (w *LvlWithStruct) CondPrint(cond bool, x ...interface{}) {
+	if cond {
+		if w.l.anyIgnore(true) {
+			return
+		}
+		_ = w.out(w.kv, fmt.Sprint(x...))
+	}
+}
+
+// CondPrintf - conditional version of Printf
+func (w *LvlWithStruct) CondPrintf(cond bool, f string, x ...interface{}) {
+	if cond {
+		if w.l.anyIgnore(true) {
+			return
+		}
+		_ = w.out(w.kv, fmt.Sprintf(f, x...))
+	}
+}
+
+// CondPrintln - conditional version of Println
+func (w *LvlWithStruct) CondPrintln(cond bool, x ...interface{}) {
+	if cond {
+		if w.l.anyIgnore(true) {
+			return
+		}
+		_ = w.out(w.kv, fmt.Sprintln(x...))
+	}
+}
+
+// joinFields - returns a new slice of a followed by b so derived
+// loggers never share a backing array.
+func joinFields(a, b []interface{}) []interface{} {
+	kv := make([]interface{}, 0, len(a)+len(b))
+	kv = append(kv, a...)
+	return append(kv, b...)
+}
+
+// appendFields - appends key=value pairs of kv to message s keeping
+// any trailing newline at the end.
+func appendFields(s string, kv []interface{}) string {
+	nl := strings.HasSuffix(s, "\n")
+	if nl {
+		s = s[:len(s)-1]
+	}
+	if s != "" {
+		s += " "
+	}
+	s += fmtFields(kv)
+	if nl {
+		s += "\n"
+	}
+	return s
+}
+
+// fmtFields - returns kv as space separated key=value pairs, an odd
+// trailing element is output as !BADKEY=value.
+func fmtFields(kv []interface{}) string {
+	var b strings.Builder
+	for i := 0; i < len(kv); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if i+1 == len(kv) {
+			b.WriteString("!BADKEY=")
+			b.WriteString(quoteField(fmt.Sprint(kv[i])))
+			break
+		}
+		b.WriteString(quoteField(fmt.Sprint(kv[i])))
+		b.WriteByte('=')
+		b.WriteString(quoteField(fmt.Sprint(kv[i+1])))
+	}
+	return b.String()
+}
+
+// quoteField - quotes s if it is empty or contains spaces, '=', '"'
+// or non printable characters.
+func quoteField(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -41,12 +41,110 @@ func (g *GlvlStruct) SetIgnoreAll(state bool) {
 	g.ignoreall = state
 }
 
+// Lvl - returns group LvlStruct of level v, nil if v is not a valid level.
+func (g *GlvlStruct) Lvl(v Level) *LvlStruct {
+	if v < LevelTrace || v > LevelEmergency {
+		return nil
+	}
+	return *g.lvlList()[v].level
+}
+
+// MinLevel - return minimum level of group, levels below it are ignored.
+func (g *GlvlStruct) MinLevel() Level {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.minLevel
+}
+
+// SetMinLevel - sets minimum level of group, levels below it are ignored
+// i.e. SetMinLevel(LevelWarning) ignores Trace, Debug, Info and Notice.
+// Individual level ignore states are left untouched.
+func (g *GlvlStruct) SetMinLevel(v Level) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.minLevel = v
+}
+
 // SetLabel - applies group string to all grplog levels.
 func (g *GlvlStruct) SetLabel(glabel string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.label = glabel
+	for _, v := range g.lvlList() {
+		(*v.level).log.SetPrefix(glabel + levelCol(v.Blab, g.alignLvl))
+	}
+}
+
+// levelCol - returns base label blab, padded to the widest base label if pad.
+func levelCol(blab string, pad bool) string {
+	if pad {
+		return align(blab, len(EmergencyBlab))
+	}
+	return blab
+}
+
+// AlignLevel - returns true if base labels are padded to a fixed width.
+func (g *GlvlStruct) AlignLevel() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.alignLvl
+}
+
+// SetAlignLevel - pads base labels of all group levels to the widest
+// so mixed level output lines up i.e. "glog:INFO:      ", reverting
+// prefixes to group label plus base label as SetLabel does.
+func (g *GlvlStruct) SetAlignLevel(b bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.alignLvl = b
+	for _, v := range g.lvlList() {
+		(*v.level).log.SetPrefix(g.label + levelCol(v.Blab, b))
+	}
+}
+
+// AlignFile - returns group alignment [minimum width] for filename stuff.
+func (g *GlvlStruct) AlignFile() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.logAlignFile
+}
+
+// SetAlignFile - sets alignment for filename stuff of all group levels,
+// see LvlStruct SetAlignFile.
+func (g *GlvlStruct) SetAlignFile(minWidth int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.logAlignFile = clampAlign(minWidth, LogAlignFileMax)
+	for _, v := range g.lvlList() {
+		(*v.level).align.filea = g.logAlignFile
+	}
+}
+
+// AlignFunc - returns group alignment [width] for funcname stuff.
+func (g *GlvlStruct) AlignFunc() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.logAlignFunc
+}
+
+// SetAlignFunc - sets alignment for funcname stuff of all group levels,
+// see LvlStruct SetAlignFunc.
+func (g *GlvlStruct) SetAlignFunc(minWidth int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.logAlignFunc = clampAlign(minWidth, LogAlignFuncMax)
+	for _, v := range g.lvlList() {
+		(*v.level).align.funca = g.logAlignFunc
+	}
+}
+
+// SetEncoder - sets output encoder for all group log levels,
+// nil reverts to the classic prefix format.
+func (g *GlvlStruct) SetEncoder(enc Encoder) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	for _, v := range g.lvlList() {
-		(*v.level).log.SetPrefix(glabel + v.Blab)
+		(*v.level).enc = enc
 	}
 }
 
@@ -64,33 +162,41 @@ func (g *GlvlStruct) SetOutput(w io.Writer) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	for _, v := range g.lvlList() {
-		(*v.level).log.SetOutput(w)
 		(*v.level).logOutput = w
+		(*v.level).log.SetOutput((*v.level).writer())
+		(*v.level).colorll()
 	}
 }
 
 // Println - calls Println for each level of the group with args passed in.
 func (g *GlvlStruct) Println(x ...interface{}) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	for _, v := range g.lvlList() {
-		if (*v.level).anyIgnore(false) {
-			continue
-		}
-		_ = (*v.level).outll(0, fmt.Sprintln(x...))
-	}
+	g.out(fmt.Sprintln(x...))
 }
 
 // CondPrintln - conditional version of Println
 func (g *GlvlStruct) CondPrintln(cond bool, x ...interface{}) {
 	if cond {
-		g.mu.Lock()
-		defer g.mu.Unlock()
-		for _, v := range g.lvlList() {
-			if (*v.level).anyIgnore(false) {
-				continue
-			}
-			_ = (*v.level).outll(0, fmt.Sprintln(x...))
+		g.out(fmt.Sprintln(x...))
+	}
+}
+
+// out - outputs s to each level of the group not ignored, write errors
+// are handled per level error policy once group lock is released.
+func (g *GlvlStruct) out(s string) {
+	var errl []*LvlStruct
+	var errs []error
+	g.mu.Lock()
+	for _, v := range g.lvlList() {
+		if (*v.level).anyIgnore(false) {
+			continue
 		}
+		if err := (*v.level).outll(1, nil, s); err != nil {
+			errl = append(errl, *v.level)
+			errs = append(errs, err)
+		}
+	}
+	g.unlock()
+	for i, l := range errl {
+		l.outErr(errs[i], s)
 	}
 }
@@ -5,6 +5,7 @@
 package grplog_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/phcurtis/grplog"
@@ -22,3 +23,66 @@ func TestSetIgnoreAll(t *testing.T) {
 		}
 	}
 }
+
+func TestSetMinLevel(t *testing.T) {
+	g := grplog.MustNew("glog:", 0)
+	if got := g.MinLevel(); got != grplog.LevelTrace {
+		t.Errorf("[%s].MinLevel() default got:%v want:%v\n", g.Name, got, grplog.LevelTrace)
+	}
+	var buf bytes.Buffer
+	g.SetOutput(&buf)
+	g.SetFlags(0)
+	g.SetMinLevel(grplog.LevelWarning)
+	if got := g.MinLevel(); got != grplog.LevelWarning {
+		t.Errorf("[%s].MinLevel() got:%v want:%v\n", g.Name, got, grplog.LevelWarning)
+	}
+	g.Notice.Println("notice")
+	g.Warning.Println("warning")
+	g.Println("group")
+	want := "glog:WARNING: warning\n" +
+		"glog:WARNING: group\n" +
+		"glog:ALERT: group\n" +
+		"glog:ERROR: group\n" +
+		"glog:CRITICAL: group\n" +
+		"glog:EMERGENCY: group\n"
+	if got := buf.String(); got != want {
+		t.Errorf("[%s].SetMinLevel(%v) got:%q want:%q\n", g.Name, grplog.LevelWarning, got, want)
+	}
+	if g.Notice.Ignore() {
+		t.Errorf("[%s].SetMinLevel should not change Notice.Ignore()", g.Name)
+	}
+}
+
+func TestAlignColumns(t *testing.T) {
+	g := grplog.MustNew("alog:", grplog.FfnBase)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf bytes.Buffer
+	g.SetOutput(&buf)
+
+	if g.AlignFile() != grplog.LogAlignFileDef || g.AlignFunc() != grplog.LogAlignFuncDef || g.AlignLevel() {
+		t.Errorf("defaults got file:%d func:%d level:%v", g.AlignFile(), g.AlignFunc(), g.AlignLevel())
+	}
+	g.SetAlignFile(grplog.LogAlignFileMax + 10)
+	g.SetAlignFunc(36)
+	g.SetAlignLevel(true)
+	if g.AlignFile() != grplog.LogAlignFileMax || g.Error.AlignFile() != grplog.LogAlignFileMax || g.Trace.AlignFunc() != 36 {
+		t.Errorf("got group file:%d Error file:%d Trace func:%d", g.AlignFile(), g.Error.AlignFile(), g.Trace.AlignFunc())
+	}
+
+	g.Info.Println("a")
+	g.Emergency.Println("b")
+	want := "alog:INFO:      FN:grplog_test.TestAlignColumns()   a\n" +
+		"alog:EMERGENCY: FN:grplog_test.TestAlignColumns()   b\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	g.SetAlignFunc(20)
+	g.SetAlignLevel(false)
+	buf.Reset()
+	g.Info.Println("c")
+	if got, want := buf.String(), "alog:INFO: FN:~tAlignColumns() c\n"; got != want {
+		t.Errorf("truncated got:%q want:%q", got, want)
+	}
+}
@@ -7,6 +7,12 @@
 // Critical, Emergency. Having them grouped easily allows multiple sets within a
 // program as well as providing a way to easily grep the output coming from that
 // group.  i.e.  glog:TRACE: glog:DEBUG versus blog:TRACE: blog:ERROR  ....
+//
+// Every group created by New, MustNew, NewSpecial or NewSpecialEnc is added to
+// a package registry [see Groups, Lookup, LookupLabel] used by admin, config and
+// env functions. The registry holds a reference so a group is never garbage
+// collected until Close or Unregister is called; programs creating short lived
+// groups must call one of them when done with a group.
 package grplog
 
 import (
@@ -16,6 +22,7 @@ import (
 	"log"
 	"os"
 	"sync"
+	"time"
 )
 
 // Version of this package
@@ -73,6 +80,7 @@ var Gtrace = LvlStruct{
 	mu:        new(sync.Mutex),
 	logOutput: os.Stdout,
 	name:      "Gtrace",
+	lvl:       LevelTrace,
 	align:     alignStruct{filea: LogAlignFileDef, funca: LogAlignFuncDef},
 }
 
@@ -86,13 +94,33 @@ type LvlStruct struct {
 	logOutput  io.Writer   // maintain copy since log.logger does not support Get Output
 	outCtr     uint64      // counter of times func 'out' called
 	outCharCtr uint64      // counter of chars sent through func 'out' and onto log.logger
+	ignoreCtr  uint64      // counter of calls ignored
+	errCtr     uint64      // counter of write errors
+	lastWrite  time.Time   // time of last write
+	errPolicy  ErrPolicy   // what to do when a write fails
+	fallback   io.Writer   // written to on failure when errPolicy is ErrFallback
 	name       string      // go entryPoint name
+	lvl        Level       // severity of this level
 	align      alignStruct //
+	enc        Encoder     // if nil classic prefix format via log.logger
+	limit      *rateStruct // if non nil rate limits output
+	suppCtr    uint64      // counter of messages suppressed by rate limit
+	dedup      *dupStruct  // if non nil collapses repeated messages
+	sample     *sampStruct // if non nil samples output per call site
+	sampCtr    uint64      // counter of messages dropped by sampling
+	hooks      []Hook      // called with each record output
+	fired      []*Record   // records output awaiting hooks, see unlock
+	color      ColorMode   // whether prefix is colored
+	colorCode  string      // ANSI escape coloring prefix, empty uses DefaultPalette
+	colorOn    bool        // prefix is colored per color and output
+	stack      StackMode   // call stack appended to messages
 }
 
 // GlvlStruct - group log level struct
 type GlvlStruct struct {
-	ignoreall    bool // way to ignore Print,Printf,Println, CondPrint, CondPrintln
+	ignoreall    bool  // way to ignore Print,Printf,Println, CondPrint, CondPrintln
+	minLevel     Level // levels below this are ignored
+	label        string
 	Name         string
 	Trace        *LvlStruct
 	Debug        *LvlStruct
@@ -103,10 +131,14 @@ type GlvlStruct struct {
 	Error        *LvlStruct
 	Critical     *LvlStruct
 	Emergency    *LvlStruct
-	mu           sync.Mutex // mutex for group
+	mu           sync.Mutex   // mutex for group
+	async        *asyncStruct // non nil when group is in async mode
+	errHandler   ErrorHandler // called on write failure of ErrCallback levels
 	firstIowr    IowrStruct
 	logAlignFile int
 	logAlignFunc int
+	alignLvl     bool // base labels padded to the widest
+	hookErr      HookErrorHandler
 }
 
 // IowrStruct - grplog iowriters struct
@@ -158,6 +190,7 @@ const (
 
 type lvlListStruct struct {
 	level **LvlStruct
+	lvl   Level
 	name  string
 	Blab  string
 	iowr  *io.Writer
@@ -165,15 +198,15 @@ type lvlListStruct struct {
 
 func (g *GlvlStruct) lvlList() []lvlListStruct {
 	return []lvlListStruct{
-		{&g.Trace, "Trace", TraceBlab, &g.firstIowr.Trace},
-		{&g.Debug, "Debug", DebugBlab, &g.firstIowr.Debug},
-		{&g.Info, "Info", InfoBlab, &g.firstIowr.Info},
-		{&g.Notice, "Notice", NoticeBlab, &g.firstIowr.Notice},
-		{&g.Warning, "Warning", WarningBlab, &g.firstIowr.Warning},
-		{&g.Alert, "Alert", AlertBlab, &g.firstIowr.Alert},
-		{&g.Error, "Error", ErrorBlab, &g.firstIowr.Error},
-		{&g.Critical, "Critical", CriticalBlab, &g.firstIowr.Critical},
-		{&g.Emergency, "Emergency", EmergencyBlab, &g.firstIowr.Emergency},
+		{&g.Trace, LevelTrace, "Trace", TraceBlab, &g.firstIowr.Trace},
+		{&g.Debug, LevelDebug, "Debug", DebugBlab, &g.firstIowr.Debug},
+		{&g.Info, LevelInfo, "Info", InfoBlab, &g.firstIowr.Info},
+		{&g.Notice, LevelNotice, "Notice", NoticeBlab, &g.firstIowr.Notice},
+		{&g.Warning, LevelWarning, "Warning", WarningBlab, &g.firstIowr.Warning},
+		{&g.Alert, LevelAlert, "Alert", AlertBlab, &g.firstIowr.Alert},
+		{&g.Error, LevelError, "Error", ErrorBlab, &g.firstIowr.Error},
+		{&g.Critical, LevelCritical, "Critical", CriticalBlab, &g.firstIowr.Critical},
+		{&g.Emergency, LevelEmergency, "Emergency", EmergencyBlab, &g.firstIowr.Emergency},
 	}
 }
 
@@ -184,18 +217,33 @@ func (g *GlvlStruct) lvlList() []lvlListStruct {
 //	- logFlagsGroup is stdlib log - log flags value to apply to all levels of logging
 // 	- iowr is to contain corresponding iowriters for all logging levels
 //		[one could use ioutil.Discard to inactivate a level of logging]
+// The group is registered [see Groups] and stays in memory until Close or
+// Unregister is called.
 func NewSpecial(glabel string, flags int, logFlagsGroup int, iowr IowrStruct) (*GlvlStruct, error) {
 	return newll(glabel, flags, logFlagsGroup, &iowr, false)
 }
 
+// NewSpecialEnc ... same as NewSpecial however output of all levels of
+// logging is formatted by enc i.e. JSONEncoder{} instead of the classic
+// prefix format. A nil enc is the same as NewSpecial.
+func NewSpecialEnc(glabel string, flags int, logFlagsGroup int, iowr IowrStruct, enc Encoder) (*GlvlStruct, error) {
+	g, err := newll(glabel, flags, logFlagsGroup, &iowr, false)
+	if err != nil {
+		return nil, err
+	}
+	g.SetEncoder(enc)
+	return g, nil
+}
+
 // New ... returns *GlvlStruct and error based on following arguments.
-// see NewSpecial on input parameters.
+// see NewSpecial on input parameters. The group is registered [see Groups]
+// and stays in memory until Close or Unregister is called.
 func New(glabel string, flags int) (*GlvlStruct, error) {
 	return newll(glabel, flags, LflagsDef, nil, false)
 }
 
 // MustNew returns *GlvlStruct and panics if any error occurs
-// see NewSpecial on input parameters
+// see NewSpecial on input parameters and registration
 func MustNew(glabel string, flags int) *GlvlStruct {
 	g, _ := newll(glabel, flags, LflagsDef, nil, true)
 	return g
@@ -206,7 +254,8 @@ var muGrplogCount sync.Mutex
 
 // newll - worker func that creates a new blogStruct
 func newll(glabel string, flags int, logFlags int, iowr *IowrStruct, panicErr bool) (*GlvlStruct, error) {
-	g := &GlvlStruct{firstIowr: IowrDefault()}
+	g := &GlvlStruct{firstIowr: IowrDefault(), label: glabel,
+		logAlignFile: LogAlignFileDef, logAlignFunc: LogAlignFuncDef}
 	if iowr != nil {
 		g.firstIowr = *iowr
 	}
@@ -232,8 +281,10 @@ func newll(glabel string, flags int, logFlags int, iowr *IowrStruct, panicErr bo
 			mu:        &g.mu,
 			par:       g,
 			name:      v.name,
+			lvl:       v.lvl,
 			align:     alignStruct{filea: LogAlignFileDef, funca: LogAlignFuncDef},
 		}
 	}
+	register(g)
 	return g, nil
 }
@@ -0,0 +1,185 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"runtime"
+	"time"
+)
+
+// Hook - called with each Record output by the levels it is added to,
+// i.e. to forward errors to an alerting system or count messages.
+// Fire is called without any grplog lock held so it may itself log, and
+// the Record is not reused after Fire returns. A Hook added to several
+// levels or groups must be safe for concurrent use.
+type Hook interface {
+	Levels() []Level      // levels hook applies to, nil means all
+	Fire(r *Record) error // see HookErrorHandler
+}
+
+// HookErrorHandler - called with the hook, record and error when a Hook
+// Fire returns an error.
+type HookErrorHandler func(h Hook, r *Record, err error)
+
+// hookLevel - returns true if hook h applies to level v.
+func hookLevel(h Hook, v Level) bool {
+	lvls := h.Levels()
+	if lvls == nil {
+		return true
+	}
+	for _, lv := range lvls {
+		if lv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// AddHook - adds h to level if h applies to level, see Hook.
+func (l *LvlStruct) AddHook(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addHookll(h)
+}
+
+func (l *LvlStruct) addHookll(h Hook) {
+	if hookLevel(h, l.lvl) {
+		// copy on write as hooks are called without lock
+		l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], h)
+	}
+}
+
+// RemoveHook - removes h from level.
+func (l *LvlStruct) RemoveHook(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeHookll(h)
+}
+
+func (l *LvlStruct) removeHookll(h Hook) {
+	var hooks []Hook
+	for _, v := range l.hooks {
+		if v != h {
+			hooks = append(hooks, v)
+		}
+	}
+	l.hooks = hooks
+}
+
+// Hooks - returns hooks of level.
+func (l *LvlStruct) Hooks() []Hook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.hooks[:len(l.hooks):len(l.hooks)]
+}
+
+// AddHook - adds h to each group log level it applies to, see Hook.
+func (g *GlvlStruct) AddHook(h Hook) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).addHookll(h)
+	}
+}
+
+// RemoveHook - removes h from all group log levels.
+func (g *GlvlStruct) RemoveHook(h Hook) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).removeHookll(h)
+	}
+}
+
+// SetHookErrorHandler - sets handler called when a hook of any group
+// log level fails, nil discards hook errors.
+func (g *GlvlStruct) SetHookErrorHandler(h HookErrorHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.hookErr = h
+}
+
+// hookRecord - queues a Record of message s for level hooks, call site
+// is resolved from c.pc when flags exclude it. Must be called with lock held.
+func (l *LvlStruct) hookRecord(c callerStruct, kv []interface{}, s string) {
+	r := l.record(c, kv, s)
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if (r.File == "" || r.Func == "") && c.pc != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{c.pc}).Next()
+		if r.File == "" {
+			r.File, r.Line = f.File, f.Line
+		}
+		if r.Func == "" {
+			r.Func = f.Function
+		}
+	}
+	l.fired = append(l.fired, r)
+}
+
+// unlock - releases level lock then sends any async messages stashed,
+// handles failed async writes and fires hooks for records output while
+// it was held.
+func (l *LvlStruct) unlock() {
+	a, stash := l.par.takeStash()
+	if len(l.fired) == 0 {
+		l.mu.Unlock()
+		a.send(stash)
+		a.handleFails()
+		return
+	}
+	recs, hooks, herr := l.takeFired()
+	l.mu.Unlock()
+	a.send(stash)
+	a.handleFails()
+	fire(recs, hooks, herr)
+}
+
+// takeFired - returns and clears records queued for hooks along with
+// hooks and handler to fire them with. Must be called with lock held.
+func (l *LvlStruct) takeFired() ([]*Record, []Hook, HookErrorHandler) {
+	recs := l.fired
+	l.fired = nil
+	var herr HookErrorHandler
+	if l.par != nil {
+		herr = l.par.hookErr
+	}
+	return recs, l.hooks, herr
+}
+
+// unlock - same as LvlStruct unlock for all group log levels.
+func (g *GlvlStruct) unlock() {
+	type firing struct {
+		recs  []*Record
+		hooks []Hook
+		herr  HookErrorHandler
+	}
+	var list []firing
+	for _, v := range g.lvlList() {
+		if l := *v.level; len(l.fired) > 0 {
+			recs, hooks, herr := l.takeFired()
+			list = append(list, firing{recs, hooks, herr})
+		}
+	}
+	a, stash := g.takeStash()
+	g.mu.Unlock()
+	a.send(stash)
+	a.handleFails()
+	for _, f := range list {
+		fire(f.recs, f.hooks, f.herr)
+	}
+}
+
+// fire - calls each hook for each record. Must be called without lock held.
+func fire(recs []*Record, hooks []Hook, herr HookErrorHandler) {
+	for _, r := range recs {
+		for _, h := range hooks {
+			if err := h.Fire(r); err != nil && herr != nil {
+				herr(h, r, err)
+			}
+		}
+	}
+}
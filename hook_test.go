@@ -0,0 +1,89 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+type testHook struct {
+	lvls []grplog.Level
+	recs []grplog.Record
+	err  error
+}
+
+func (h *testHook) Levels() []grplog.Level { return h.lvls }
+
+func (h *testHook) Fire(r *grplog.Record) error {
+	h.recs = append(h.recs, *r)
+	return h.err
+}
+
+func TestHooks(t *testing.T) {
+	g := grplog.MustNew("hlog:", grplog.FfnBase)
+	defer g.Close()
+	g.SetFlags(0)
+	g.SetOutput(ioutil.Discard)
+
+	alert := &testHook{lvls: []grplog.Level{grplog.LevelError, grplog.LevelCritical, grplog.LevelEmergency}}
+	all := &testHook{}
+	g.AddHook(alert)
+	g.Warning.AddHook(all)
+	if len(g.Error.Hooks()) != 1 || len(g.Info.Hooks()) != 0 || len(g.Warning.Hooks()) != 1 {
+		t.Errorf("hooks got Error:%d Info:%d Warning:%d", len(g.Error.Hooks()), len(g.Info.Hooks()), len(g.Warning.Hooks()))
+	}
+
+	g.Info.Println("skip")
+	g.Error.With("code", 7).Println("disk full")
+	g.Warning.Printf("low space %d%%", 5)
+	g.Println("group")
+	g.Critical.SetIgnore(true)
+	g.Critical.Println("ignored")
+
+	if len(alert.recs) != 4 {
+		t.Fatalf("alert hook got %d records want 4: %+v", len(alert.recs), alert.recs)
+	}
+	r := alert.recs[0]
+	if r.Group != "hlog" || r.Level != grplog.LevelError || r.Msg != "disk full" ||
+		len(r.Fields) != 2 || r.Func != "grplog_test.TestHooks" ||
+		!strings.HasSuffix(r.File, "hook_test.go") || r.Line == 0 || r.Time.IsZero() {
+		t.Errorf("alert record got:%+v", r)
+	}
+	if r := alert.recs[1]; r.Level != grplog.LevelError || r.Msg != "group" {
+		t.Errorf("alert record 1 got:%+v", r)
+	}
+	if r := alert.recs[2]; r.Level != grplog.LevelCritical || r.Msg != "group" {
+		t.Errorf("alert record 2 got:%+v", r)
+	}
+	if r := alert.recs[3]; r.Level != grplog.LevelEmergency || r.Msg != "group" {
+		t.Errorf("alert record 3 got:%+v", r)
+	}
+	if len(all.recs) != 2 || all.recs[0].Msg != "low space 5%" {
+		t.Errorf("warning hook got:%+v", all.recs)
+	}
+
+	// hook errors go to handler, hooks may log without deadlock
+	var herrs []error
+	g.SetHookErrorHandler(func(h grplog.Hook, r *grplog.Record, err error) {
+		herrs = append(herrs, err)
+		g.Info.Println("hook failed:", err)
+	})
+	alert.err = errors.New("pager down")
+	g.Error.Println("again")
+	if len(herrs) != 1 || herrs[0] != alert.err {
+		t.Errorf("hook errors got:%v", herrs)
+	}
+
+	g.RemoveHook(alert)
+	g.Error.Println("no hook")
+	if len(alert.recs) != 5 {
+		t.Errorf("after RemoveHook got %d records want 5", len(alert.recs))
+	}
+}
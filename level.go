@@ -0,0 +1,58 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Level - ordered severity of a grplog log level, Trace being the lowest
+// and Emergency the highest. The order matches the group lvlList order.
+type Level int
+
+// grplog levels in increasing order of severity
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelNotice
+	LevelWarning
+	LevelAlert
+	LevelError
+	LevelCritical
+	LevelEmergency
+)
+
+var levelNames = [...]string{
+	LevelTrace:     "Trace",
+	LevelDebug:     "Debug",
+	LevelInfo:      "Info",
+	LevelNotice:    "Notice",
+	LevelWarning:   "Warning",
+	LevelAlert:     "Alert",
+	LevelError:     "Error",
+	LevelCritical:  "Critical",
+	LevelEmergency: "Emergency",
+}
+
+// String - returns level name i.e. "Trace", "Debug" ...
+func (v Level) String() string {
+	if v < LevelTrace || v > LevelEmergency {
+		return "Level(" + strconv.Itoa(int(v)) + ")"
+	}
+	return levelNames[v]
+}
+
+// ParseLevel - returns Level named s [case insensitive] i.e. "debug", "WARNING".
+func ParseLevel(s string) (Level, error) {
+	for i, v := range levelNames {
+		if strings.EqualFold(s, v) {
+			return Level(i), nil
+		}
+	}
+	return LevelTrace, errors.New("grplog: unknown level " + strconv.Quote(s))
+}
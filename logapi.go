@@ -27,7 +27,7 @@ func (l *LvlStruct) Print(x ...interface{}) {
 	if l.anyIgnore(true) {
 		return
 	}
-	_ = l.out(fmt.Sprint(x...))
+	_ = l.out(nil, fmt.Sprint(x...))
 }
 
 // Fatalf stdlib.log Fatalf but possible decorate, etc.
@@ -45,7 +45,7 @@ func (l *LvlStruct) Printf(f string, x ...interface{}) {
 	if l.anyIgnore(true) {
 		return
 	}
-	_ = l.out(fmt.Sprintf(f, x...))
+	_ = l.out(nil, fmt.Sprintf(f, x...))
 }
 
 // Fatalln - stdlib.log level Fatalln but possible decorate, etc.
@@ -63,5 +63,15 @@ func (l *LvlStruct) Println(x ...interface{}) {
 	if l.anyIgnore(true) {
 		return
 	}
-	_ = l.out(fmt.Sprintln(x...))
+	_ = l.out(nil, fmt.Sprintln(x...))
+}
+
+// Output - stdlib.log Output, outputs s for the call site calldepth frames
+// up where 1 is the caller of Output, unless level is ignored. For use by
+// logging helpers and wrappers, see also WithCallerSkip.
+func (l *LvlStruct) Output(calldepth int, s string) error {
+	if l.anyIgnore(true) {
+		return nil
+	}
+	return l.outd(calldepth-1, nil, s)
 }
@@ -10,12 +10,23 @@ import (
 )
 
 // AnyIgnore - returns true if level or parent group has "ignore" set
+// or level is below parent group minimum level.
 func (l *LvlStruct) anyIgnore(protect bool) bool {
 	if protect {
 		l.mu.Lock()
 		defer l.mu.Unlock()
 	}
-	return l.ignore || (l.par != nil && l.par.ignoreall)
+	if l.ignored() {
+		l.ignoreCtr++
+		return true
+	}
+	return false
+}
+
+// ignored - returns true if l is ignored without counting it,
+// caller must hold l.mu.
+func (l *LvlStruct) ignored() bool {
+	return l.ignore || (l.par != nil && (l.par.ignoreall || l.lvl < l.par.minLevel))
 }
 
 // CondPrint - conditional version of Print
@@ -24,7 +35,7 @@ func (l *LvlStruct) CondPrint(cond bool, x ...interface{}) {
 		if l.anyIgnore(true) {
 			return
 		}
-		_ = l.out(fmt.Sprint(x...))
+		_ = l.out(nil, fmt.Sprint(x...))
 	}
 }
 
@@ -34,7 +45,7 @@ func (l *LvlStruct) CondPrintf(cond bool, f string, x ...interface{}) {
 		if l.anyIgnore(true) {
 			return
 		}
-		_ = l.out(fmt.Sprintf(f, x...))
+		_ = l.out(nil, fmt.Sprintf(f, x...))
 	}
 }
 
@@ -44,7 +55,7 @@ func (l *LvlStruct) CondPrintln(cond bool, x ...interface{}) {
 		if l.anyIgnore(true) {
 			return
 		}
-		_ = l.out(fmt.Sprintln(x...))
+		_ = l.out(nil, fmt.Sprintln(x...))
 	}
 }
 
@@ -59,12 +70,7 @@ func (l *LvlStruct) AlignFile() int {
 func (l *LvlStruct) SetAlignFile(minWidth int) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if minWidth > LogAlignFileMax {
-		minWidth = LogAlignFileMax
-	} else if minWidth < 0 {
-		minWidth = 0
-	}
-	l.align.filea = minWidth
+	l.align.filea = clampAlign(minWidth, LogAlignFileMax)
 }
 
 // AlignFunc - return alignment [minimum width] for funcname stuff
@@ -74,16 +80,27 @@ func (l *LvlStruct) AlignFunc() int {
 	return l.align.funca
 }
 
-// SetAlignFunc - set alignment [minimum width] for funcname stuff
+// SetAlignFunc - set alignment [width] for funcname stuff, a longer
+// "FN:name() " is truncated keeping the tail of name marked by '~'.
 func (l *LvlStruct) SetAlignFunc(minWidth int) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if minWidth > LogAlignFuncMax {
-		minWidth = LogAlignFuncMax
-	} else if minWidth < 0 {
-		minWidth = 0
+	l.align.funca = clampAlign(minWidth, LogAlignFuncMax)
+}
+
+// clampAlign - returns alignment width limited to 0 through max.
+func clampAlign(width, max int) int {
+	if width > max {
+		return max
+	} else if width < 0 {
+		return 0
 	}
-	l.align.funca = minWidth
+	return width
+}
+
+// Level - returns severity level of this log level.
+func (l *LvlStruct) Level() Level {
+	return l.lvl
 }
 
 // Flags - returns the log flags.
@@ -127,8 +144,9 @@ func (l *LvlStruct) GetOutput() io.Writer {
 func (l *LvlStruct) SetOutput(w io.Writer) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.log.SetOutput(w)
 	l.logOutput = w
+	l.log.SetOutput(l.writer())
+	l.colorll()
 }
 
 // Prefix - returns 'prefix' label.
@@ -150,6 +168,20 @@ func (l *LvlStruct) SetPrefix(prefix string) {
 	l.log.SetPrefix(prefix)
 }
 
+// Encoder - returns output encoder, nil being the classic prefix format.
+func (l *LvlStruct) Encoder() Encoder {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc
+}
+
+// SetEncoder - sets output encoder, nil reverts to the classic prefix format.
+func (l *LvlStruct) SetEncoder(enc Encoder) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enc = enc
+}
+
 // PkgFlags -
 func (l *LvlStruct) PkgFlags() int {
 	l.mu.Lock()
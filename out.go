@@ -19,23 +19,45 @@ var osExit = os.Exit
 
 func (l *LvlStruct) outExit(s string) {
 	l.mu.Lock()
-	defer l.mu.Unlock()
-	_ = l.outll(1, s)
+	if l.dedup != nil {
+		// pending repeat count goes before final message
+		_ = l.dedupFlush(l.dedup)
+	}
+	// never rate limited
+	_ = l.outw(l.caller(2), nil, s)
+	l.unlock()
+	l.flushAsync()
 	osExit(1)
 }
 
 func (l *LvlStruct) outPanic(s string) {
 	l.mu.Lock()
-	defer l.mu.Unlock()
-	_ = l.outll(1, s)
+	if l.dedup != nil {
+		// pending repeat count goes before final message
+		_ = l.dedupFlush(l.dedup)
+	}
+	// never rate limited
+	_ = l.outw(l.caller(2), nil, s)
+	l.unlock()
+	l.flushAsync()
 	panic(s)
 }
 
-func (l *LvlStruct) out(s string) error {
+func (l *LvlStruct) out(kv []interface{}, s string) error {
+	return l.outd(1, kv, s)
+}
+
+// outd - same as out with call site skip frames above the caller of outd.
+func (l *LvlStruct) outd(skip int, kv []interface{}, s string) error {
 	// may have to re-examine having this lock in place for entire func
 	l.mu.Lock()
-	defer l.mu.Unlock()
-	return l.outll(1, s)
+	err := l.outll(1+skip, kv, s)
+	l.unlock()
+	if err != nil {
+		// handled without lock so ErrorHandler may itself log
+		l.outErr(err, s)
+	}
+	return err
 }
 
 func align(str string, width int) string {
@@ -46,24 +68,129 @@ func align(str string, width int) string {
 	return str
 }
 
-// out - a worker func that does final prep before calling stdlib log.Output.
-func (l *LvlStruct) outll(lvladj int, s string) error {
-	l.outCtr++
-	//fmt.Printf("%s:outCtr:%d\n", l.name, l.outCtr)
+// funcCol - returns "FN:fname() " padded to width, or if longer truncated
+// to width keeping the tail of fname marked by a leading '~'.
+func funcCol(fname string, width int) string {
+	fns := "FN:" + fname + "() "
+	if width <= 0 {
+		return fns
+	}
+	if over := len(fns) - width; over > 0 && len(fname) > over+1 {
+		return "FN:~" + fname[over+1:] + "() "
+	}
+	return align(fns, width)
+}
 
-	var fns string
+// callerStruct - call site details resolved per level flags.
+type callerStruct struct {
+	pc    uintptr // program counter of call site, 0 if unknown
+	file  string  // per log flags Lshortfile or Llongfile [less go path src], else empty
+	line  int     //
+	fname string  // per pkg flags FfnBase or FfnFull, else empty
+}
+
+// caller - resolves call site lvl frames above the func calling caller.
+func (l *LvlStruct) caller(lvl int) callerStruct {
+	var c callerStruct
 	switch {
 	case l.flags&FfnBase > 0:
-		fns = "FN:" + fn.LvlBase(2+lvladj) + "() "
+		c.fname = fn.LvlBase(lvl + 1)
 	case l.flags&FfnFull > 0:
-		fns = "FN:" + fn.Lvl(2+lvladj) + "() "
+		c.fname = fn.Lvl(lvl + 1)
 	default:
 	}
 
+	pc, file, line, _ := runtime.Caller(lvl + 1)
+	c.pc = pc
+	// if log flags are including filename
+	if l.log.Flags()&(log.Lshortfile|log.Llongfile) > 0 {
+		c.file, c.line = l.trimFile(file), line
+	}
+	return c
+}
+
+// callerPC - resolves call site of program counter pc, as caller does.
+func (l *LvlStruct) callerPC(pc uintptr) callerStruct {
+	c := callerStruct{pc: pc}
+	if pc == 0 {
+		return c
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	switch {
+	case l.flags&FfnBase > 0:
+		c.fname = filepath.Base(f.Function)
+	case l.flags&FfnFull > 0:
+		c.fname = f.Function
+	default:
+	}
+
+	if l.log.Flags()&(log.Lshortfile|log.Llongfile) > 0 {
+		c.file, c.line = l.trimFile(f.File), f.Line
+	}
+	return c
+}
+
+// trimFile - returns filename per log flags Lshortfile or Llongfile
+// [and pkg flags Ffilenogps].
+func (l *LvlStruct) trimFile(file string) string {
+	if l.log.Flags()&log.Lshortfile > 0 {
+		return filepath.Base(file)
+	}
+	// log.Llongfile
+	if l.flags&Ffilenogps > 0 {
+		if strings.HasPrefix(file, gopathsrc) {
+			file = file[len(gopathsrc):]
+		}
+	}
+	return file
+}
+
+// out - a worker func that does final prep before calling stdlib log.Output.
+func (l *LvlStruct) outll(lvladj int, kv []interface{}, s string) error {
+	return l.outc(l.caller(2+lvladj), kv, s)
+}
+
+// outc - worker func of outll with call site c already resolved.
+func (l *LvlStruct) outc(c callerStruct, kv []interface{}, s string) error {
+	if l.sampled(c.pc) {
+		return nil
+	}
+	if l.dedup != nil && l.repeated(c, kv, s) {
+		return nil
+	}
+	if l.limited() {
+		return nil
+	}
+	return l.outw(c, kv, s)
+}
+
+// outw - writes s with call site c and fields kv regardless of rate limit.
+func (l *LvlStruct) outw(c callerStruct, kv []interface{}, s string) error {
+	l.outCtr++
+	if l.stack != StackOff && c.pc != 0 {
+		s = l.appendStack(c.pc, s)
+	}
+	if len(l.hooks) > 0 {
+		l.hookRecord(c, kv, s)
+	}
+	//fmt.Printf("%s:outCtr:%d\n", l.name, l.outCtr)
+
+	if _, ok := l.logOutput.(RecordWriter); ok || l.enc != nil {
+		return l.outRec(c, kv, s)
+	}
+
+	var fns string
+	if c.fname != "" {
+		fns = funcCol(c.fname, l.align.funca)
+	}
+
+	if len(kv) > 0 {
+		s = appendFields(s, kv)
+	}
+
 	l.outCharCtr += uint64(len(fns) + len(s))
 	//fmt.Printf("%s:outCharCtr:%d\n", l.name, l.outCharCtr)
 
-	lvl := 2 + lvladj
 	var filenlr string
 
 	// get original [current] log flags
@@ -73,22 +200,11 @@ func (l *LvlStruct) outll(lvladj int, s string) error {
 
 	// if log flags are including filename
 	if lfn > 0 {
-		_, file, line, _ := runtime.Caller(lvl)
-		linenum := fmt.Sprintf(":%d", line)
-		if orgflags&log.Lshortfile > 0 {
-			file = filepath.Base(file)
-		} else {
-			// log.Llongfile
-			if l.flags&Ffilenogps > 0 {
-				if strings.HasPrefix(file, gopathsrc) {
-					file = file[len(gopathsrc):]
-				}
-			}
+		if c.file != "" {
+			filenlr = c.file + fmt.Sprintf(":%d", c.line) + " "
+			filenlr = align(filenlr, l.align.filea)
 		}
 
-		filenlr = file + linenum + " "
-		filenlr = align(filenlr, l.align.filea)
-
 		// set log flags not to include filename
 		l.log.SetFlags(orgflags &^ sl)
 	}
@@ -96,10 +212,18 @@ func (l *LvlStruct) outll(lvladj int, s string) error {
 
 	// as of go 1.9 ... stdlib log does not check err,
 	// we do here and you can decide to panic by so configuring a given level.
+	var orgprefix string
+	if l.colorOn {
+		orgprefix = l.log.Prefix()
+		l.log.SetPrefix(l.colorPrefix(orgprefix))
+	}
 	err := l.log.Output(3, filenlr+fns+s)
 	if lfn > 0 {
 		// restore log flags
 		l.log.SetFlags(orgflags)
 	}
-	return err
+	if l.colorOn {
+		l.log.SetPrefix(orgprefix)
+	}
+	return l.outStat(err)
 }
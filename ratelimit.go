@@ -0,0 +1,116 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateSummaryDef - default interval of "suppressed N messages" summary lines.
+const RateSummaryDef = 10 * time.Second
+
+// rateStruct - token bucket rate limiter of a level.
+type rateStruct struct {
+	rate    float64       // tokens added per second
+	burst   float64       // bucket capacity
+	tokens  float64       //
+	last    time.Time     // time tokens last added
+	every   time.Duration // interval of summary lines
+	supp    uint64        // suppressed since last summary
+	pending bool          // summary timer is running
+}
+
+func newRate(rate float64, burst int, every time.Duration) *rateStruct {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	if every <= 0 {
+		every = RateSummaryDef
+	}
+	return &rateStruct{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now(), every: every}
+}
+
+// allow - takes a token if one is available.
+func (r *rateStruct) allow(now time.Time) bool {
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// limited - returns true if message is to be dropped per level rate limit,
+// arranging for a summary line of dropped messages. Must be called with lock held.
+func (l *LvlStruct) limited() bool {
+	r := l.limit
+	if r == nil || r.allow(time.Now()) {
+		return false
+	}
+	l.suppCtr++
+	r.supp++
+	if !r.pending {
+		r.pending = true
+		time.AfterFunc(r.every, func() { l.rateSummary(r) })
+	}
+	return true
+}
+
+// rateSummary - outputs count of messages suppressed by r during its interval.
+func (l *LvlStruct) rateSummary(r *rateStruct) {
+	l.mu.Lock()
+	n := r.supp
+	r.supp = 0
+	r.pending = false
+	if n == 0 || l.limit != r {
+		l.mu.Unlock()
+		return
+	}
+	s := fmt.Sprintf("suppressed %d messages in last %v", n, r.every)
+	err := l.outw(callerStruct{}, nil, s)
+	l.unlock()
+	if err != nil {
+		l.outErr(err, s)
+	}
+}
+
+// RateLimit - returns level rate limit in messages per second and burst,
+// rate is 0 if not limited.
+func (l *LvlStruct) RateLimit() (rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit == nil {
+		return 0, 0
+	}
+	return l.limit.rate, int(l.limit.burst)
+}
+
+// SetRateLimit - limits level output to rate messages per second allowing
+// bursts of up to burst messages, excess messages are dropped and a line
+// "suppressed N messages in last <every>" is output at most once per every
+// [0 means RateSummaryDef]. A rate <= 0 removes the limit.
+func (l *LvlStruct) SetRateLimit(rate float64, burst int, every time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = newRate(rate, burst, every)
+}
+
+// SetRateLimit - same as LvlStruct SetRateLimit for all group log levels,
+// each level having its own limit.
+func (g *GlvlStruct) SetRateLimit(rate float64, burst int, every time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).limit = newRate(rate, burst, every)
+	}
+}
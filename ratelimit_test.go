@@ -0,0 +1,77 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestRateLimit(t *testing.T) {
+	g := grplog.MustNew("rlog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf syncBuffer
+	g.SetOutput(&buf)
+	g.SetRateLimit(0.001, 3, 20*time.Millisecond)
+	if rate, burst := g.Info.RateLimit(); rate != 0.001 || burst != 3 {
+		t.Errorf("RateLimit got rate:%v burst:%d", rate, burst)
+	}
+	g.Error.SetRateLimit(0, 0, 0)
+	if rate, _ := g.Error.RateLimit(); rate != 0 {
+		t.Errorf("Error RateLimit got rate:%v want 0", rate)
+	}
+
+	for i := 0; i < 10; i++ {
+		g.Info.Println("retry", i)
+		g.Error.Println("fail", i)
+	}
+	g.Println("group")
+
+	if s := g.Info.Stats(); s.Msgs != 3 || s.Suppressed != 8 {
+		t.Errorf("Info stats got:%+v want Msgs:3 Suppressed:8", s)
+	}
+	if s := g.Error.Stats(); s.Msgs != 11 || s.Suppressed != 0 {
+		t.Errorf("Error stats got:%+v want Msgs:11 Suppressed:0", s)
+	}
+
+	want := "rlog:INFO: suppressed 8 messages in last 20ms\n"
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), want) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	out := buf.String()
+	if !strings.Contains(out, want) {
+		t.Errorf("missing summary line %q in:\n%s", want, out)
+	}
+	if strings.Contains(out, "retry 3") || !strings.Contains(out, "retry 2") {
+		t.Errorf("wrong messages suppressed in:\n%s", out)
+	}
+	if strings.Count(out, "suppressed") != 1 {
+		t.Errorf("want one summary line got:\n%s", out)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"strings"
+	"sync"
+)
+
+// registry of groups, every group is registered on creation and pinned
+// in memory until Unregister or Close
+var registry = struct {
+	sync.Mutex
+	groups []*GlvlStruct
+}{}
+
+func register(g *GlvlStruct) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.groups = append(registry.groups, g)
+}
+
+// Unregister - removes g from the registry of groups, g remains usable
+// and may be garbage collected once no longer referenced.
+// Returns false if g was not registered.
+func Unregister(g *GlvlStruct) bool {
+	registry.Lock()
+	defer registry.Unlock()
+	for i, v := range registry.groups {
+		if v == g {
+			registry.groups = append(registry.groups[:i], registry.groups[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Groups - returns all registered groups in creation order.
+func Groups() []*GlvlStruct {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]*GlvlStruct(nil), registry.groups...)
+}
+
+// Lookup - returns registered group with unique Name i.e. "glog:<3>",
+// nil if none.
+func Lookup(name string) *GlvlStruct {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, g := range registry.groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// LookupLabel - returns registered groups whose current label is glabel,
+// a trailing ':' is ignored so "glog" and "glog:" are the same label.
+func LookupLabel(glabel string) []*GlvlStruct {
+	glabel = strings.TrimSuffix(glabel, ":")
+	var list []*GlvlStruct
+	for _, g := range Groups() {
+		if strings.TrimSuffix(g.Label(), ":") == glabel {
+			list = append(list, g)
+		}
+	}
+	return list
+}
+
+// Label - returns group label, see SetLabel.
+func (g *GlvlStruct) Label() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.label
+}
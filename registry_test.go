@@ -0,0 +1,57 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestRegistry(t *testing.T) {
+	a := grplog.MustNew("reglog:", 0)
+	b := grplog.MustNew("reglog:", 0)
+	c := grplog.MustNew("otherlog:", 0)
+
+	if got := grplog.Lookup(b.Name); got != b {
+		t.Errorf("Lookup(%q) got:%v want:%v", b.Name, got, b)
+	}
+	if got := grplog.Lookup("nosuch:<0>"); got != nil {
+		t.Errorf("Lookup(nosuch) got:%v want:nil", got)
+	}
+	if got := grplog.LookupLabel("reglog"); len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("LookupLabel(reglog) got:%v want:[%s %s]", got, a.Name, b.Name)
+	}
+
+	found := 0
+	for _, g := range grplog.Groups() {
+		if g == a || g == b || g == c {
+			found++
+		}
+	}
+	if found != 3 {
+		t.Errorf("Groups() found:%d want:3", found)
+	}
+
+	c.SetLabel("reglog:")
+	if got := grplog.LookupLabel("reglog:"); len(got) != 3 {
+		t.Errorf("LookupLabel after SetLabel got:%d groups want:3", len(got))
+	}
+
+	if !grplog.Unregister(a) {
+		t.Errorf("Unregister(%s) got:false want:true", a.Name)
+	}
+	if grplog.Unregister(a) {
+		t.Errorf("second Unregister(%s) got:true want:false", a.Name)
+	}
+	_ = b.Close()
+	_ = c.Close()
+	if got := grplog.LookupLabel("reglog"); len(got) != 0 {
+		t.Errorf("LookupLabel after Close got:%v want:[]", got)
+	}
+	if got := grplog.Lookup(b.Name); got != nil {
+		t.Errorf("Lookup(%q) after Close got:%v want:nil", b.Name, got)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOpts - rotation settings for a RotateStruct, zero values disable
+// the corresponding rotation or cleanup.
+type RotateOpts struct {
+	MaxSize    int64         // rotate before a write would exceed this many bytes
+	MaxAge     time.Duration // remove backups older than this
+	MaxBackups int           // keep at most this many backups
+	Compress   bool          // gzip rotated files
+	Daily      bool          // rotate at local midnight
+}
+
+// rotateTimeFmt - backup filename suffix, sorts in time order.
+const rotateTimeFmt = "20060102-150405.000000"
+
+// RotateStruct - a rotating file io.Writer which can be assigned to any
+// IowrStruct field or SetOutput. It is safe to share across levels and groups.
+// Rotated files are named filename.<yyyymmdd-hhmmss.micros>[-N][.gz]
+// where -N distinguishes rotations within the same microsecond.
+type RotateStruct struct {
+	filename string
+	opts     RotateOpts
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	next     time.Time        // next daily rotation
+	now      func() time.Time // clock, replaced in tests
+}
+
+// NewRotate - opens [appending to] or creates filename [cleaned per
+// filepath.Clean] and returns a RotateStruct that rotates it per opts.
+func NewRotate(filename string, opts RotateOpts) (*RotateStruct, error) {
+	r := &RotateStruct{filename: filepath.Clean(filename), opts: opts, now: time.Now}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.openll(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Filename - returns name of the current [active] file.
+func (r *RotateStruct) Filename() string {
+	return r.filename
+}
+
+// Write - writes p to current file rotating first as needed.
+func (r *RotateStruct) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		if err := r.openll(); err != nil {
+			return 0, err
+		}
+	}
+	due := r.opts.Daily && !r.now().Before(r.next)
+	if r.opts.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.opts.MaxSize {
+		due = true
+	}
+	if due {
+		if err := r.rotatell(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate - forces a rotation of current file.
+func (r *RotateStruct) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotatell()
+}
+
+// Close - closes current file, a subsequent Write reopens it.
+func (r *RotateStruct) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *RotateStruct) openll() error {
+	f, err := os.OpenFile(r.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.f = f
+	r.size = fi.Size()
+	now := r.now()
+	r.next = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	return nil
+}
+
+// rotatell - renames current file to a backup, opens a new one and
+// then compresses and cleans up backups per opts.
+func (r *RotateStruct) rotatell() error {
+	if r.f != nil {
+		if err := r.f.Close(); err != nil {
+			return err
+		}
+		r.f = nil
+	}
+	base := r.filename + "." + r.now().Format(rotateTimeFmt)
+	backup := base
+	for i := 1; exists(backup) || exists(backup+".gz"); i++ {
+		backup = base + "-" + strconv.Itoa(i)
+	}
+	if err := os.Rename(r.filename, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := r.openll(); err != nil {
+		return err
+	}
+	if r.opts.Compress {
+		if err := gzipFile(backup); err != nil {
+			return err
+		}
+	}
+	return r.cleanupll()
+}
+
+// exists - returns true if name exists.
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// backupTime - returns rotation time and sequence of backup file name,
+// ok is false if name is not a backup of r.
+func (r *RotateStruct) backupTime(name string) (t time.Time, seq int, ok bool) {
+	prefix := filepath.Base(r.filename) + "."
+	ts := strings.TrimSuffix(name, ".gz")
+	if !strings.HasPrefix(ts, prefix) {
+		return t, 0, false
+	}
+	ts = ts[len(prefix):]
+	if n := len(rotateTimeFmt); len(ts) > n+1 && ts[n] == '-' {
+		var err error
+		if seq, err = strconv.Atoi(ts[n+1:]); err != nil {
+			return t, 0, false
+		}
+		ts = ts[:n]
+	}
+	t, err := time.ParseInLocation(rotateTimeFmt, ts, time.Local)
+	return t, seq, err == nil
+}
+
+type backupStruct struct {
+	name string
+	t    time.Time
+	seq  int
+}
+
+// backups - returns backup files oldest first.
+func (r *RotateStruct) backups() ([]backupStruct, error) {
+	dir := filepath.Dir(r.filename)
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var list []backupStruct
+	for _, fi := range fis {
+		if t, seq, ok := r.backupTime(fi.Name()); ok {
+			list = append(list, backupStruct{filepath.Join(dir, fi.Name()), t, seq})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].t.Equal(list[j].t) {
+			return list[i].t.Before(list[j].t)
+		}
+		return list[i].seq < list[j].seq
+	})
+	return list, nil
+}
+
+func (r *RotateStruct) cleanupll() error {
+	if r.opts.MaxBackups <= 0 && r.opts.MaxAge <= 0 {
+		return nil
+	}
+	list, err := r.backups()
+	if err != nil {
+		return err
+	}
+	cutoff := r.now().Add(-r.opts.MaxAge)
+	for i, v := range list {
+		remove := r.opts.MaxBackups > 0 && len(list)-i > r.opts.MaxBackups
+		if !remove && r.opts.MaxAge > 0 {
+			remove = v.t.Before(cutoff)
+		}
+		if remove {
+			if err := os.Remove(v.name); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gzipFile - compresses name into name.gz and removes name.
+func gzipFile(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err == nil {
+		err = zw.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(name + ".gz")
+		return err
+	}
+	_ = in.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock - returns a clock starting at t advancing 1ms per call.
+func fakeClock(t time.Time) func() time.Time {
+	return func() time.Time {
+		t = t.Add(time.Millisecond)
+		return t
+	}
+}
+
+func Test_rotatesize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogRotate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "app.log")
+	r, err := NewRotate(name, RotateOpts{MaxSize: 10, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = fakeClock(time.Date(2017, 11, 15, 10, 0, 0, 0, time.Local))
+
+	g := MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(r)
+	for i := 0; i < 4; i++ {
+		g.Info.Print(i) // 13 bytes each so every write after first rotates
+	}
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+
+	list, err := r.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("backups got:%d want:2 %v", len(list), list)
+	}
+	for i, v := range list {
+		if !strings.HasSuffix(v.name, ".gz") {
+			t.Errorf("backup %q not compressed", v.name)
+			continue
+		}
+		f, err := os.Open(v.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(zr)
+		_ = f.Close()
+		if want := "glog:INFO: " + string(rune('1'+i)) + "\n"; string(b) != want {
+			t.Errorf("backup %q got:%q want:%q", v.name, b, want)
+		}
+	}
+	b, _ := ioutil.ReadFile(name)
+	if want := "glog:INFO: 3\n"; string(b) != want {
+		t.Errorf("current got:%q want:%q", b, want)
+	}
+}
+
+func Test_rotatedaily(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogRotate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "app.log")
+	r, err := NewRotate(name, RotateOpts{Daily: true, MaxAge: 48 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pre existing backup older than MaxAge
+	old := name + "." + time.Date(2017, 11, 1, 0, 0, 0, 0, time.Local).Format(rotateTimeFmt)
+	if err := ioutil.WriteFile(old, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2017, 11, 15, 23, 59, 0, 0, time.Local)
+	r.now = func() time.Time { return now }
+	r.next = time.Date(2017, 11, 16, 0, 0, 0, 0, time.Local)
+	_, _ = r.Write([]byte("day1\n"))
+	now = now.Add(2 * time.Minute)
+	_, _ = r.Write([]byte("day2\n"))
+	_ = r.Close()
+
+	list, _ := r.backups()
+	if len(list) != 1 {
+		t.Fatalf("backups got:%v want 1 backup", list)
+	}
+	if b, _ := ioutil.ReadFile(list[0].name); string(b) != "day1\n" {
+		t.Errorf("backup got:%q want:%q", b, "day1\n")
+	}
+	if b, _ := ioutil.ReadFile(name); string(b) != "day2\n" {
+		t.Errorf("current got:%q want:%q", b, "day2\n")
+	}
+}
+
+func Test_rotateuncleanname(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogRotate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	name := dir + "/sub/..//app.log"
+	r, err := NewRotate(name, RotateOpts{MaxSize: 10, MaxBackups: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = fakeClock(time.Date(2017, 11, 15, 10, 0, 0, 0, time.Local))
+	for i := 0; i < 4; i++ {
+		_, _ = r.Write([]byte("0123456789\n"))
+	}
+	_ = r.Close()
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app.log.*"))
+	if len(matches) != 1 {
+		t.Errorf("backups got:%v want 1 backup", matches)
+	}
+}
+
+func Test_rotatecollision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogRotate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "app.log")
+	r, err := NewRotate(name, RotateOpts{MaxSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2017, 11, 15, 10, 0, 0, 0, time.Local)
+	r.now = func() time.Time { return now }
+	for _, v := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n"} {
+		_, _ = r.Write([]byte(v))
+	}
+	_ = r.Close()
+
+	list, err := r.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("backups got:%d want:3 %v", len(list), list)
+	}
+	for i, v := range list {
+		if v.seq != i {
+			t.Errorf("backup %q seq got:%d want:%d", v.name, v.seq, i)
+		}
+		want := string(rune('a'+i)) + string(rune('a'+i))
+		want = want + want + "\n"
+		if b, _ := ioutil.ReadFile(v.name); string(b) != want {
+			t.Errorf("backup %q got:%q want:%q", v.name, b, want)
+		}
+	}
+}
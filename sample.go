@@ -0,0 +1,92 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"time"
+)
+
+// sampStruct - per call site sampler of a level.
+type sampStruct struct {
+	interval   time.Duration        // counts restart each interval
+	first      uint64               // messages output per site per interval
+	thereafter uint64               // then every thereafter message, 0 drops all
+	sites      map[uintptr]*siteCtr // keyed by call site pc
+}
+
+type siteCtr struct {
+	beg time.Time // start of current interval
+	n   uint64    // messages this interval
+}
+
+// sampled - returns true if message from call site pc is to be dropped
+// per level sampling. Must be called with lock held.
+func (l *LvlStruct) sampled(pc uintptr) bool {
+	sp := l.sample
+	if sp == nil {
+		return false
+	}
+	now := time.Now()
+	sc := sp.sites[pc]
+	if sc == nil {
+		sc = &siteCtr{beg: now}
+		sp.sites[pc] = sc
+	} else if now.Sub(sc.beg) >= sp.interval {
+		sc.beg, sc.n = now, 0
+	}
+	sc.n++
+	if sc.n <= sp.first || (sp.thereafter > 0 && (sc.n-sp.first)%sp.thereafter == 0) {
+		return false
+	}
+	l.sampCtr++
+	return true
+}
+
+// Sampling - returns level sampling settings, interval is 0 if not sampling.
+func (l *LvlStruct) Sampling() (interval time.Duration, first, thereafter int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sample == nil {
+		return 0, 0, 0
+	}
+	return l.sample.interval, int(l.sample.first), int(l.sample.thereafter)
+}
+
+// SetSampling - outputs only the first messages from each call site per
+// interval and then every thereafter message [0 drops the rest], dropped
+// messages are counted in stats. An interval <= 0 turns sampling off.
+func (l *LvlStruct) SetSampling(interval time.Duration, first, thereafter int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sample = newSample(interval, first, thereafter)
+}
+
+func newSample(interval time.Duration, first, thereafter int) *sampStruct {
+	if interval <= 0 {
+		return nil
+	}
+	if first < 0 {
+		first = 0
+	}
+	if thereafter < 0 {
+		thereafter = 0
+	}
+	return &sampStruct{
+		interval:   interval,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		sites:      make(map[uintptr]*siteCtr),
+	}
+}
+
+// SetSampling - same as LvlStruct SetSampling for all group log levels,
+// each level sampling on its own.
+func (g *GlvlStruct) SetSampling(interval time.Duration, first, thereafter int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).sample = newSample(interval, first, thereafter)
+	}
+}
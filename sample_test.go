@@ -0,0 +1,64 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestSampling(t *testing.T) {
+	g := grplog.MustNew("slog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf syncBuffer
+	g.SetOutput(&buf)
+	g.SetSampling(time.Hour, 2, 3)
+	if iv, first, there := g.Debug.Sampling(); iv != time.Hour || first != 2 || there != 3 {
+		t.Errorf("Sampling got %v %d %d", iv, first, there)
+	}
+	g.Trace.SetSampling(time.Hour, 1, 0)
+
+	for i := 1; i <= 10; i++ {
+		g.Debug.Println("a", i)
+	}
+	for i := 1; i <= 3; i++ {
+		g.Debug.Println("b", i) // other call site
+	}
+	for i := 1; i <= 5; i++ {
+		g.Trace.Println("t", i)
+	}
+
+	want := []string{"a 1", "a 2", "a 5", "a 8", "b 1", "b 2", "t 1"}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		got = append(got, line[strings.Index(line, ": ")+2:])
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+	if s := g.Stats(); s.Debug.Sampled != 7 || s.Debug.Msgs != 6 || s.Trace.Sampled != 4 {
+		t.Errorf("stats got Debug:%+v Trace:%+v", s.Debug, s.Trace)
+	}
+
+	// interval restarts counts
+	g.Info.SetSampling(50*time.Millisecond, 1, 0)
+	for i := 0; i < 4; i++ {
+		g.Info.Println("i")
+		if i == 1 {
+			time.Sleep(60 * time.Millisecond)
+		}
+	}
+	if s := g.Info.Stats(); s.Msgs != 2 || s.Sampled != 2 {
+		t.Errorf("Info stats got:%+v want Msgs:2 Sampled:2", s)
+	}
+	g.SetSampling(0, 0, 0)
+	if iv, _, _ := g.Debug.Sampling(); iv != 0 {
+		t.Errorf("Sampling after off got %v", iv)
+	}
+}
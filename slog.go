@@ -0,0 +1,146 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package grplog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogOpts - options for NewSlogHandler.
+type SlogOpts struct {
+	// Level maps a slog level onto a grplog level, nil uses SlogLevel.
+	Level func(slog.Level) Level
+}
+
+// SlogHandler - a slog.Handler routing each slog record to the group
+// LvlStruct its level maps to, honoring that level's ignore state, flags,
+// outputs and file/func decoration.
+type SlogHandler struct {
+	g      *GlvlStruct
+	level  func(slog.Level) Level
+	kv     []interface{} // attrs from WithAttrs, keys qualified by groups
+	prefix string        // WithGroup names joined by "." plus trailing "."
+}
+
+// NewSlogHandler - returns a slog.Handler backed by g,
+// i.e. slog.New(grplog.NewSlogHandler(g, nil)).
+func NewSlogHandler(g *GlvlStruct, opts *SlogOpts) *SlogHandler {
+	h := &SlogHandler{g: g, level: SlogLevel}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+// SlogLevel - default mapping of slog levels onto grplog levels where
+// below Debug is Trace, Debug up to Info is Debug, Info and Info+1 are Info,
+// Info+2 up to Warn is Notice, Warn and Warn+1 are Warning, Warn+2 up to
+// Error is Alert, Error up to Error+4 is Error, Error+4 up to Error+8 is
+// Critical and Error+8 and above is Emergency.
+func SlogLevel(v slog.Level) Level {
+	switch {
+	case v < slog.LevelDebug:
+		return LevelTrace
+	case v < slog.LevelInfo:
+		return LevelDebug
+	case v < slog.LevelInfo+2:
+		return LevelInfo
+	case v < slog.LevelWarn:
+		return LevelNotice
+	case v < slog.LevelWarn+2:
+		return LevelWarning
+	case v < slog.LevelError:
+		return LevelAlert
+	case v < slog.LevelError+4:
+		return LevelError
+	case v < slog.LevelError+8:
+		return LevelCritical
+	default:
+		return LevelEmergency
+	}
+}
+
+func (h *SlogHandler) lvl(v slog.Level) *LvlStruct {
+	l := h.g.Lvl(h.level(v))
+	if l == nil {
+		l = h.g.Lvl(SlogLevel(v))
+	}
+	return l
+}
+
+// Enabled - reports whether mapped level is not ignored, unlike Handle
+// it does not count ignored calls.
+func (h *SlogHandler) Enabled(_ context.Context, v slog.Level) bool {
+	l := h.lvl(v)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.ignored()
+}
+
+// Handle - outputs r via mapped level, see slog.Handler.
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	l := h.lvl(r.Level)
+	if l.anyIgnore(true) {
+		return nil
+	}
+	kv := h.kv
+	if r.NumAttrs() > 0 {
+		kv = joinFields(h.kv, nil)
+		r.Attrs(func(a slog.Attr) bool {
+			kv = appendAttr(kv, h.prefix, a)
+			return true
+		})
+	}
+
+	l.mu.Lock()
+	err := l.outc(l.callerPC(r.PC), kv, r.Message)
+	l.unlock()
+	if err != nil {
+		l.outErr(err, r.Message)
+	}
+	return err
+}
+
+// WithAttrs - returns a handler whose output includes attrs.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.kv = joinFields(h.kv, nil)
+	for _, a := range attrs {
+		h2.kv = appendAttr(h2.kv, h.prefix, a)
+	}
+	return &h2
+}
+
+// WithGroup - returns a handler qualifying subsequent attr keys with name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// appendAttr - appends a to kv as key/value pairs, group attrs are
+// flattened with their keys qualified by prefix.
+func appendAttr(kv []interface{}, prefix string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return kv
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			kv = appendAttr(kv, prefix, ga)
+		}
+		return kv
+	}
+	return append(kv, prefix+a.Key, a.Value.Any())
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package grplog_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	g := grplog.MustNew("glog:", grplog.FfnBase)
+	g.SetFlags(grplog.LflagsOff)
+	g.SetOutput(&buf)
+
+	sl := slog.New(grplog.NewSlogHandler(g, nil))
+	sl.Info("hello", "user", 42)
+	sl.With("req", "abc").WithGroup("http").Warn("slow", "ms", 1500, slog.Group("peer", "ip", "::1"))
+	sl.Log(context.Background(), slog.LevelError+8, "down")
+	sl.Debug("dbg")
+	g.SetMinLevel(grplog.LevelInfo)
+	sl.Debug("should NOT see")
+
+	want := "glog:INFO: FN:grplog_test.TestSlogHandler() hello user=42\n" +
+		"glog:WARNING: FN:grplog_test.TestSlogHandler() slow req=abc http.ms=1500 http.peer.ip=::1\n" +
+		"glog:EMERGENCY: FN:grplog_test.TestSlogHandler() down\n" +
+		"glog:DEBUG: FN:grplog_test.TestSlogHandler() dbg\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:%q\nwant:%q", got, want)
+	}
+	// Enabled does not count, only Handle does
+	if got := g.Debug.Stats().Ignored; got != 0 {
+		t.Errorf("Debug ignored got:%d want:0", got)
+	}
+	h := grplog.NewSlogHandler(g, nil)
+	if h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("Enabled(Debug) got:true want:false")
+	}
+	_ = h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelDebug, "dbg", 0))
+	if got := g.Debug.Stats().Ignored; got != 1 {
+		t.Errorf("Debug ignored got:%d want:1", got)
+	}
+
+	// file decoration and custom level mapping
+	buf.Reset()
+	g.SetFlags(grplog.LflagsDTS)
+	sl = slog.New(grplog.NewSlogHandler(g, &grplog.SlogOpts{
+		Level: func(v slog.Level) grplog.Level {
+			if v >= slog.LevelError {
+				return grplog.LevelCritical
+			}
+			return grplog.SlogLevel(v)
+		},
+	}))
+	sl.Error("crit")
+	re := regexp.MustCompile(`^glog:CRITICAL: \S+ \S+ slog_test.go:\d+ +FN:grplog_test.TestSlogHandler\(\) crit\n$`)
+	if !re.MatchString(buf.String()) {
+		t.Errorf("got:%q want match:%q", buf.String(), re)
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		in   slog.Level
+		want grplog.Level
+	}{
+		{slog.LevelDebug - 1, grplog.LevelTrace},
+		{slog.LevelDebug, grplog.LevelDebug},
+		{slog.LevelInfo, grplog.LevelInfo},
+		{slog.LevelInfo + 2, grplog.LevelNotice},
+		{slog.LevelWarn, grplog.LevelWarning},
+		{slog.LevelWarn + 2, grplog.LevelAlert},
+		{slog.LevelError, grplog.LevelError},
+		{slog.LevelError + 4, grplog.LevelCritical},
+		{slog.LevelError + 8, grplog.LevelEmergency},
+	}
+	for _, test := range tests {
+		if got := grplog.SlogLevel(test.in); got != test.want {
+			t.Errorf("SlogLevel(%v) got:%v want:%v", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackMode - what call stack a level appends to each message.
+type StackMode int
+
+// stack trace modes
+const (
+	StackOff       StackMode = iota // no stack [default]
+	StackCaller                     // call site frame only
+	StackGoroutine                  // calling goroutine from call site down
+	StackAll                        // all goroutines
+)
+
+// stackPkg - function name prefix of frames internal to this package.
+const stackPkg = "github.com/phcurtis/grplog."
+
+// stackFile - returns file trimmed per pkg flags Ffilenogps.
+func (l *LvlStruct) stackFile(file string) string {
+	if l.flags&Ffilenogps > 0 && strings.HasPrefix(file, gopathsrc) {
+		return file[len(gopathsrc):]
+	}
+	return file
+}
+
+// appendStack - returns s followed by a newline and the stack per level
+// stack mode, frames start at call site pc.
+func (l *LvlStruct) appendStack(pc uintptr, s string) string {
+	var st string
+	switch l.stack {
+	case StackCaller, StackGoroutine:
+		st = l.stackFrames(pc, l.stack == StackCaller)
+	case StackAll:
+		st = l.stackAll()
+	default:
+		return s
+	}
+	return strings.TrimSuffix(s, "\n") + "\n" + st
+}
+
+// stackFrames - returns the calling goroutine stack from call site pc
+// [or first frame outside this package if pc is not found] formatted
+// like a panic, only the first frame if one is true.
+func (l *LvlStruct) stackFrames(pc uintptr, one bool) string {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	var frames []runtime.Frame
+	start := -1
+	iter := runtime.CallersFrames(pcs)
+	for {
+		f, more := iter.Next()
+		// pc may be a return address as from runtime.Callers
+		if start < 0 && pc != 0 && (f.PC == pc || f.PC+1 == pc) {
+			start = len(frames)
+		}
+		frames = append(frames, f)
+		if !more {
+			break
+		}
+	}
+	if start < 0 {
+		for start = 0; start < len(frames)-1; start++ {
+			if !strings.HasPrefix(frames[start].Function, stackPkg) {
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("goroutine " + strconv.FormatUint(goid(), 10) + " [running]:\n")
+	for _, f := range frames[start:] {
+		b.WriteString(f.Function + "()\n\t" + l.stackFile(f.File) + ":" + strconv.Itoa(f.Line) + "\n")
+		if one {
+			break
+		}
+	}
+	return b.String()
+}
+
+// stackAll - returns stacks of all goroutines as runtime.Stack formats
+// them less pc offsets and with files trimmed.
+func (l *LvlStruct) stackAll() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	lines := strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		line = line[1:]
+		if j := strings.LastIndex(line, " +0x"); j >= 0 {
+			line = line[:j]
+		}
+		lines[i] = "\t" + l.stackFile(line)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// StackTrace - returns level stack trace mode.
+func (l *LvlStruct) StackTrace() StackMode {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stack
+}
+
+// SetStackTrace - sets the call stack appended to each level message
+// with a known call site, files are trimmed per pkg flags Ffilenogps.
+func (l *LvlStruct) SetStackTrace(m StackMode) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stack = m
+}
+
+// SetStackTrace - same as LvlStruct SetStackTrace for all group log levels.
+func (g *GlvlStruct) SetStackTrace(m StackMode) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).stack = m
+	}
+}
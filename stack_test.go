@@ -0,0 +1,62 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func stackHelper(l *grplog.LvlStruct) {
+	l.Println("failed")
+}
+
+func TestStackTrace(t *testing.T) {
+	g := grplog.MustNew("stlog:", grplog.Ffilenogps)
+	defer g.Close()
+	g.SetFlags(0)
+	var buf bytes.Buffer
+	g.SetOutput(&buf)
+
+	g.Error.SetStackTrace(grplog.StackCaller)
+	stackHelper(g.Error)
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 5 || lines[0] != "stlog:ERROR: failed" ||
+		!strings.HasPrefix(lines[1], "goroutine ") ||
+		lines[2] != "github.com/phcurtis/grplog_test.stackHelper()" ||
+		!strings.HasSuffix(lines[3], "stack_test.go:16") || lines[4] != "" {
+		t.Errorf("StackCaller got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	g.Error.SetStackTrace(grplog.StackGoroutine)
+	stackHelper(g.Error)
+	out := buf.String()
+	if !strings.Contains(out, "grplog_test.stackHelper()\n") || !strings.Contains(out, "grplog_test.TestStackTrace()\n") ||
+		strings.Contains(out, "grplog.(*LvlStruct)") {
+		t.Errorf("StackGoroutine got:\n%s", out)
+	}
+
+	buf.Reset()
+	g.SetStackTrace(grplog.StackAll)
+	g.Critical.Print("all")
+	out = buf.String()
+	if !strings.HasPrefix(out, "stlog:CRITICAL: all\ngoroutine ") || strings.Count(out, "goroutine ") < 2 || strings.Contains(out, " +0x") {
+		t.Errorf("StackAll got:\n%s", out)
+	}
+	if g.Info.StackTrace() != grplog.StackAll {
+		t.Errorf("StackTrace got:%v", g.Info.StackTrace())
+	}
+
+	buf.Reset()
+	g.SetStackTrace(grplog.StackOff)
+	g.Error.Println("plain")
+	if buf.String() != "stlog:ERROR: plain\n" {
+		t.Errorf("StackOff got:%q", buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"time"
+)
+
+// LvlStats - output counters of a given log level.
+type LvlStats struct {
+	Msgs       uint64    // messages output
+	Bytes      uint64    // bytes output [less log.logger prefix and header]
+	Ignored    uint64    // calls ignored due to level or group ignore state
+	WriteErrs  uint64    // messages whose write returned an error
+	Suppressed uint64    // messages dropped by rate limit
+	Sampled    uint64    // messages dropped by sampling
+	LastWrite  time.Time // time of last successful write, zero if none
+}
+
+// GrpStats - LvlStats for each level of a group.
+type GrpStats struct {
+	Trace     LvlStats
+	Debug     LvlStats
+	Info      LvlStats
+	Notice    LvlStats
+	Warning   LvlStats
+	Alert     LvlStats
+	Error     LvlStats
+	Critical  LvlStats
+	Emergency LvlStats
+}
+
+// outStat - updates write counters per err, returns err.
+func (l *LvlStruct) outStat(err error) error {
+	if err != nil {
+		l.errCtr++
+	} else {
+		l.lastWrite = time.Now()
+	}
+	return err
+}
+
+func (l *LvlStruct) statsll() LvlStats {
+	return LvlStats{
+		Msgs:       l.outCtr,
+		Bytes:      l.outCharCtr,
+		Ignored:    l.ignoreCtr,
+		WriteErrs:  l.errCtr,
+		Suppressed: l.suppCtr,
+		Sampled:    l.sampCtr,
+		LastWrite:  l.lastWrite,
+	}
+}
+
+func (l *LvlStruct) resetStatsll() {
+	l.outCtr = 0
+	l.outCharCtr = 0
+	l.ignoreCtr = 0
+	l.errCtr = 0
+	l.suppCtr = 0
+	l.sampCtr = 0
+	l.lastWrite = time.Time{}
+}
+
+// Stats - returns a snapshot of level output counters.
+func (l *LvlStruct) Stats() LvlStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.statsll()
+}
+
+// ResetStats - zeros level output counters.
+func (l *LvlStruct) ResetStats() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.resetStatsll()
+}
+
+// Stats - returns a snapshot of output counters of all group levels.
+func (g *GlvlStruct) Stats() GrpStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return GrpStats{
+		Trace:     g.Trace.statsll(),
+		Debug:     g.Debug.statsll(),
+		Info:      g.Info.statsll(),
+		Notice:    g.Notice.statsll(),
+		Warning:   g.Warning.statsll(),
+		Alert:     g.Alert.statsll(),
+		Error:     g.Error.statsll(),
+		Critical:  g.Critical.statsll(),
+		Emergency: g.Emergency.statsll(),
+	}
+}
+
+// ResetStats - zeros output counters of all group levels.
+func (g *GlvlStruct) ResetStats() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.lvlList() {
+		(*v.level).resetStatsll()
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestStats(t *testing.T) {
+	g := grplog.MustNew("glog:", 0)
+	g.SetFlags(0)
+	g.SetOutput(ioutil.Discard)
+	g.Error.SetOutput(errWriter{})
+
+	g.Info.Println("12345")
+	g.Info.Print("123")
+	g.Info.SetIgnore(true)
+	g.Info.Println("ignored")
+	g.SetMinLevel(grplog.LevelWarning)
+	g.Debug.Println("ignored")
+	g.Error.Println("fails")
+	g.Println("all")
+
+	s := g.Stats()
+	if want := (grplog.LvlStats{Msgs: 2, Bytes: 9, Ignored: 2, LastWrite: s.Info.LastWrite}); s.Info != want {
+		t.Errorf("Info stats got:%+v want:%+v", s.Info, want)
+	}
+	if s.Info.LastWrite.IsZero() {
+		t.Errorf("Info LastWrite is zero")
+	}
+	if s.Debug.Msgs != 0 || s.Debug.Ignored != 2 {
+		t.Errorf("Debug stats got:%+v want Msgs:0 Ignored:2", s.Debug)
+	}
+	if s.Error.Msgs != 2 || s.Error.WriteErrs != 2 || !s.Error.LastWrite.IsZero() {
+		t.Errorf("Error stats got:%+v want Msgs:2 WriteErrs:2", s.Error)
+	}
+	if got := g.Warning.Stats(); got.Msgs != 1 || got.Bytes != 4 {
+		t.Errorf("Warning stats got:%+v want Msgs:1 Bytes:4", got)
+	}
+
+	g.ResetStats()
+	if s := g.Stats(); s != (grplog.GrpStats{}) {
+		t.Errorf("after ResetStats got:%+v", s)
+	}
+	g.Warning.Println("x")
+	g.Warning.ResetStats()
+	if got := g.Warning.Stats(); got != (grplog.LvlStats{}) {
+		t.Errorf("after Warning.ResetStats got:%+v", got)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFormat - syslog message format.
+type SyslogFormat int
+
+// syslog message formats
+const (
+	SyslogRFC5424 SyslogFormat = iota // IETF syslog with structured data
+	SyslogRFC3164                     // BSD syslog
+)
+
+// syslog facilities
+const (
+	SyslogKern   = 0
+	SyslogUser   = 1
+	SyslogDaemon = 3
+	SyslogLocal0 = 16
+	SyslogLocal1 = 17
+	SyslogLocal2 = 18
+	SyslogLocal3 = 19
+	SyslogLocal4 = 20
+	SyslogLocal5 = 21
+	SyslogLocal6 = 22
+	SyslogLocal7 = 23
+)
+
+// SyslogSDID - RFC 5424 structured data id bound fields are output under.
+const SyslogSDID = "fields@32473"
+
+var syslogSeverity = [...]int{
+	LevelTrace:     7, // debug
+	LevelDebug:     7, // debug
+	LevelInfo:      6, // informational
+	LevelNotice:    5, // notice
+	LevelWarning:   4, // warning
+	LevelAlert:     1, // alert
+	LevelError:     3, // error
+	LevelCritical:  2, // critical
+	LevelEmergency: 0, // emergency
+}
+
+// SyslogSeverity - returns syslog severity of grplog level v.
+func SyslogSeverity(v Level) int {
+	if v < LevelTrace || v > LevelEmergency {
+		return syslogSeverity[LevelInfo]
+	}
+	return syslogSeverity[v]
+}
+
+// SyslogStruct - a syslog RecordWriter which can be assigned to any
+// IowrStruct field or SetOutput, each level is sent with its syslog
+// severity and any bound fields [see LvlStruct.With] as RFC 5424
+// structured data. It is safe to share across levels and groups.
+type SyslogStruct struct {
+	network  string
+	raddr    string
+	format   SyslogFormat
+	facility int
+	tag      string
+	hostname string
+	mu       sync.Mutex
+	conn     net.Conn
+}
+
+// NewSyslog - connects to syslog daemon at raddr over network which is
+// one of "unix", "unixgram", "udp" or "tcp". An empty network connects
+// to the local syslog unix socket. tag defaults to program name.
+func NewSyslog(network, raddr string, format SyslogFormat, facility int, tag string) (*SyslogStruct, error) {
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	s := &SyslogStruct{
+		network:  network,
+		raddr:    raddr,
+		format:   format,
+		facility: facility,
+		tag:      tag,
+		hostname: hostname,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.connectll(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SyslogStruct) connectll() error {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if s.network != "" {
+		c, err := net.Dial(s.network, s.raddr)
+		if err != nil {
+			return err
+		}
+		s.conn = c
+		return nil
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range []string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+			if c, err := net.Dial(network, path); err == nil {
+				s.conn = c
+				return nil
+			}
+		}
+	}
+	return errors.New("grplog: unix syslog delivery error")
+}
+
+// Write - sends p as a single message at Info severity.
+func (s *SyslogStruct) Write(p []byte) (int, error) {
+	r := &Record{Level: LevelInfo, Msg: strings.TrimSuffix(string(p), "\n")}
+	if err := s.WriteRecord(r); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteRecord - sends r at the syslog severity of its level, see RecordWriter.
+func (s *SyslogStruct) WriteRecord(r *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var b []byte
+	if s.format == SyslogRFC3164 {
+		b = s.format3164(r)
+	} else {
+		b = s.format5424(r)
+	}
+	if s.network == "tcp" || s.network == "unix" {
+		b = append(b, '\n')
+	}
+	var err error
+	if s.conn != nil {
+		if _, err = s.conn.Write(b); err == nil {
+			return nil
+		}
+	}
+	// reconnect once
+	if err = s.connectll(); err != nil {
+		return err
+	}
+	_, err = s.conn.Write(b)
+	return err
+}
+
+// Close - closes connection to syslog daemon.
+func (s *SyslogStruct) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// sysmsg - returns message decorated like the classic format
+// i.e. file:line FN:fname() msg
+func sysmsg(r *Record) string {
+	m := r.Msg
+	if r.Func != "" {
+		m = "FN:" + r.Func + "() " + m
+	}
+	if r.File != "" {
+		m = r.File + ":" + strconv.Itoa(r.Line) + " " + m
+	}
+	return m
+}
+
+func (s *SyslogStruct) pri(r *Record) string {
+	return "<" + strconv.Itoa(s.facility*8+SyslogSeverity(r.Level)) + ">"
+}
+
+// format5424 - <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func (s *SyslogStruct) format5424(r *Record) []byte {
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	msgid := r.Group
+	if msgid == "" {
+		msgid = "-"
+	}
+	return []byte(fmt.Sprintf("%s1 %s %s %s %d %s %s %s", s.pri(r),
+		t.Format("2006-01-02T15:04:05.000000Z07:00"), s.hostname, s.tag,
+		os.Getpid(), sdName(msgid), sdata(r.Fields), sysmsg(r)))
+}
+
+// format3164 - <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+func (s *SyslogStruct) format3164(r *Record) []byte {
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	m := sysmsg(r)
+	if len(r.Fields) > 0 {
+		m = appendFields(m, r.Fields)
+	}
+	if r.Group != "" {
+		m = r.Group + ":" + levelLabel(r.Level) + ": " + m
+	}
+	return []byte(fmt.Sprintf("%s%s %s %s[%d]: %s", s.pri(r),
+		t.Format(time.Stamp), s.hostname, s.tag, os.Getpid(), m))
+}
+
+// sdata - returns kv as RFC 5424 structured data or "-" if none.
+func sdata(kv []interface{}) string {
+	if len(kv) == 0 {
+		return "-"
+	}
+	var b strings.Builder
+	b.WriteString("[" + SyslogSDID)
+	for i := 0; i < len(kv); i += 2 {
+		key, val := "!BADKEY", kv[i]
+		if i+1 < len(kv) {
+			key, val = fmt.Sprint(kv[i]), kv[i+1]
+		}
+		b.WriteString(" " + sdName(key) + `="`)
+		for _, r := range fmt.Sprint(val) {
+			if r == '"' || r == '\\' || r == ']' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// sdName - returns s as a valid RFC 5424 SD-NAME, printable us-ascii
+// less '=', ' ', ']', '"' and at most 32 chars.
+func sdName(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/phcurtis/grplog"
+)
+
+func syslogIowr(w *grplog.SyslogStruct) grplog.IowrStruct {
+	return grplog.IowrStruct{Trace: w, Debug: w, Info: w, Notice: w,
+		Warning: w, Alert: w, Error: w, Critical: w, Emergency: w}
+}
+
+func readPacket(t *testing.T, c net.PacketConn) string {
+	buf := make([]byte, 2048)
+	_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := c.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+func TestSyslogUDP(t *testing.T) {
+	c, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer c.Close()
+
+	w, err := grplog.NewSyslog("udp", c.LocalAddr().String(), grplog.SyslogRFC5424, grplog.SyslogUser, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	g, err := grplog.NewSpecial("glog:", grplog.FfnBase, grplog.LflagsOff, syslogIowr(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Error.With("user", 42, "q", `a"]`).Println("boom")
+	re := regexp.MustCompile(`^<11>1 \S+ \S+ app \d+ glog \[fields@32473 user="42" q="a\\"\\]"\] FN:grplog_test.TestSyslogUDP\(\) boom$`)
+	if got := readPacket(t, c); !re.MatchString(got) {
+		t.Errorf("got:%q want match %q", got, re)
+	}
+
+	g.Trace.Print("trace")
+	re = regexp.MustCompile(`^<15>1 .* glog - FN:grplog_test.TestSyslogUDP\(\) trace$`)
+	if got := readPacket(t, c); !re.MatchString(got) {
+		t.Errorf("got:%q want match %q", got, re)
+	}
+}
+
+func TestSyslogUnixgram(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grplogSyslog-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "log")
+	c, err := net.ListenPacket("unixgram", sock)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer c.Close()
+
+	w, err := grplog.NewSyslog("unixgram", sock, grplog.SyslogRFC3164, grplog.SyslogLocal0, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	g, err := grplog.NewSpecial("blog:", grplog.FlagsOff, grplog.LflagsOff, syslogIowr(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Emergency.Printw("down", "host", "h1")
+	re := regexp.MustCompile(`^<128>\w{3} [ \d]\d \d\d:\d\d:\d\d \S+ app\[\d+\]: blog:EMERGENCY: down host=h1$`)
+	if got := readPacket(t, c); !re.MatchString(got) {
+		t.Errorf("got:%q want match %q", got, re)
+	}
+	g.Notice.Println("n")
+	re = regexp.MustCompile(`^<133>.*: blog:NOTICE: n$`)
+	if got := readPacket(t, c); !re.MatchString(got) {
+		t.Errorf("got:%q want match %q", got, re)
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package grplog
+
+import "syscall"
+
+const ioctlReadTermios = syscall.TIOCGETA
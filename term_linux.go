@@ -0,0 +1,9 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import "syscall"
+
+const ioctlReadTermios = syscall.TCGETS
@@ -0,0 +1,20 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package grplog
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminalFile - returns true if terminal attributes of f can be read.
+func isTerminalFile(f *os.File) bool {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ioctlReadTermios, uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
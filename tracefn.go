@@ -0,0 +1,141 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phcurtis/fn"
+)
+
+// TraceFnIndent - indentation per nesting depth of TraceFnBeg output.
+var TraceFnIndent = "  "
+
+// TraceFnStruct - token returned by TraceFnBeg to be passed to TraceFnEnd.
+type TraceFnStruct struct {
+	l     *LvlStruct
+	fname string
+	beg   time.Time
+	gid   uint64
+	depth int
+}
+
+// per goroutine nesting depth of TraceFnBeg calls
+var traceDepth = struct {
+	sync.Mutex
+	m map[uint64]int
+}{m: make(map[uint64]int)}
+
+// goid - returns current goroutine id parsed from its stack header.
+func goid() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// traceBeg - returns a token for fname at the current goroutine depth
+// and increments that depth.
+func (l *LvlStruct) traceBeg(fname string) *TraceFnStruct {
+	t := &TraceFnStruct{l: l, fname: fname, beg: time.Now(), gid: goid()}
+	traceDepth.Lock()
+	t.depth = traceDepth.m[t.gid]
+	traceDepth.m[t.gid] = t.depth + 1
+	traceDepth.Unlock()
+	return t
+}
+
+// traceEnd - restores goroutine depth to that of t.
+func (t *TraceFnStruct) traceEnd() {
+	traceDepth.Lock()
+	if t.depth == 0 {
+		delete(traceDepth.m, t.gid)
+	} else {
+		traceDepth.m[t.gid] = t.depth
+	}
+	traceDepth.Unlock()
+}
+
+// traceFname - returns funcname lvl frames above caller, full if pkg
+// flags has FfnFull else base.
+func (l *LvlStruct) traceFname(lvl int) string {
+	if f := l.PkgFlags(); f&FfnBase == 0 && f&FfnFull > 0 {
+		return fn.Lvl(lvl + 1)
+	}
+	return fn.LvlBase(lvl + 1)
+}
+
+// traceFnBeg - worker for TraceFnBeg, the traced func is skip frames
+// above the caller of traceFnBeg.
+func (l *LvlStruct) traceFnBeg(skip int) *TraceFnStruct {
+	if l.anyIgnore(true) {
+		return nil
+	}
+	t := l.traceBeg(l.traceFname(skip + 1))
+	l.traceOut(1+skip, strings.Repeat(TraceFnIndent, t.depth)+"enter FN:"+t.fname+"()")
+	return t
+}
+
+// traceFnEnd - worker for TraceFnEnd logging token t via l, the traced
+// func is skip frames above the caller of traceFnEnd.
+func (l *LvlStruct) traceFnEnd(skip int, t *TraceFnStruct) {
+	if t == nil {
+		return
+	}
+	t.traceEnd()
+	if l.anyIgnore(true) {
+		return
+	}
+	l.traceOut(1+skip, strings.Repeat(TraceFnIndent, t.depth)+"exit FN:"+t.fname+"() elapsed="+time.Since(t.beg).String())
+}
+
+// traceOut - outputs s for call site skip frames above the caller of
+// traceOut without FN: decoration since s already names the func.
+func (l *LvlStruct) traceOut(skip int, s string) {
+	l.mu.Lock()
+	c := l.caller(1 + skip)
+	c.fname = ""
+	err := l.outc(c, nil, s)
+	l.unlock()
+	if err != nil {
+		// handled without lock so ErrorHandler may itself log
+		l.outErr(err, s)
+	}
+}
+
+// TraceFnBeg - logs "enter FN:x()" for the calling func x indented per
+// nesting depth of the calling goroutine and returns a token for TraceFnEnd
+// i.e. defer g.Debug.TraceFnEnd(g.Debug.TraceFnBeg())
+func (l *LvlStruct) TraceFnBeg() *TraceFnStruct {
+	return l.traceFnBeg(1)
+}
+
+// TraceFnEnd - logs "exit FN:x() elapsed=..." for token t from TraceFnBeg.
+func (l *LvlStruct) TraceFnEnd(t *TraceFnStruct) {
+	l.traceFnEnd(1, t)
+}
+
+// TraceFnBeg - same as LvlStruct TraceFnBeg using group Trace level
+// i.e. defer g.TraceFnEnd(g.TraceFnBeg())
+func (g *GlvlStruct) TraceFnBeg() *TraceFnStruct {
+	return g.Trace.traceFnBeg(1)
+}
+
+// TraceFnEnd - same as LvlStruct TraceFnEnd using level of token t.
+func (g *GlvlStruct) TraceFnEnd(t *TraceFnStruct) {
+	if t == nil {
+		return
+	}
+	t.l.traceFnEnd(1, t)
+}
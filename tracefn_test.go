@@ -0,0 +1,65 @@
+// Copyright 2017 phcurtis grplog Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grplog_test
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/phcurtis/grplog"
+)
+
+func traceInner(g *grplog.GlvlStruct) {
+	defer g.TraceFnEnd(g.TraceFnBeg())
+	g.Trace.Println("inner")
+}
+
+func traceOuter(g *grplog.GlvlStruct) {
+	defer g.TraceFnEnd(g.TraceFnBeg())
+	traceInner(g)
+}
+
+func TestTraceFn(t *testing.T) {
+	var buf bytes.Buffer
+	g := grplog.MustNew("glog:", grplog.FlagsOff)
+	g.SetFlags(0)
+	g.SetOutput(&buf)
+
+	traceOuter(g)
+	want := []string{
+		`^glog:TRACE: enter FN:grplog_test.traceOuter\(\)$`,
+		`^glog:TRACE:   enter FN:grplog_test.traceInner\(\)$`,
+		`^glog:TRACE: inner$`,
+		`^glog:TRACE:   exit FN:grplog_test.traceInner\(\) elapsed=\S+s$`,
+		`^glog:TRACE: exit FN:grplog_test.traceOuter\(\) elapsed=\S+s$`,
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines want %d:\n%s", len(got), len(want), buf.String())
+	}
+	for i := range want {
+		if !regexp.MustCompile(want[i]).MatchString(got[i]) {
+			t.Errorf("line %d got:%q want match:%q", i, got[i], want[i])
+		}
+	}
+
+	// depth restored, full funcname per pkg flags, ignored level
+	buf.Reset()
+	g.Info.SetPkgFlags(grplog.FfnFull)
+	g.Info.SetFlags(log.Lshortfile)
+	func() {
+		defer g.Info.TraceFnEnd(g.Info.TraceFnBeg())
+		g.Debug.SetIgnore(true)
+		defer g.Debug.TraceFnEnd(g.Debug.TraceFnBeg())
+	}()
+	re := regexp.MustCompile(`^glog:INFO: tracefn_test.go:\d+ +enter FN:github.com/phcurtis/grplog_test.TestTraceFn.func1\(\)\n` +
+		`glog:INFO: tracefn_test.go:\d+ +exit FN:\S+TestTraceFn.func1\(\) elapsed=\S+\n$`)
+	if !re.MatchString(buf.String()) {
+		t.Errorf("got:%q want match:%q", buf.String(), re)
+	}
+}
@@ -5,13 +5,16 @@
 package grplog
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func Test_osExit(t *testing.T) {
@@ -51,6 +54,79 @@ func Test_osExit(t *testing.T) {
 	}
 }
 
+// slowWriter - io.Writer sleeping before each write.
+type slowWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(20 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func Test_osExitAsync(t *testing.T) {
+	osExitSave := osExit
+	defer func() { osExit = osExitSave }()
+	w := &slowWriter{}
+	var got string
+	osExit = func(code int) { got = w.String() }
+
+	g := MustNew("glog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	g.SetOutput(w)
+	g.SetAsync(8, OverflowBlock)
+	g.Info.Println("one")
+	g.Error.Fatal("fatal")
+	if want := "glog:INFO: one\nglog:ERROR: fatal\n"; got != want {
+		t.Errorf("Fatal async got:%q want:%q", got, want)
+	}
+
+	func() {
+		defer func() {
+			_ = recover()
+			got = w.String()
+		}()
+		g.Error.Panic("panic")
+	}()
+	if want := "glog:INFO: one\nglog:ERROR: fatal\nglog:ERROR: panic\n"; got != want {
+		t.Errorf("Panic async got:%q want:%q", got, want)
+	}
+}
+
+func Test_osExitDedup(t *testing.T) {
+	osExitSave := osExit
+	defer func() { osExit = osExitSave }()
+	w := &slowWriter{}
+	var got string
+	osExit = func(code int) { got = w.String() }
+
+	g := MustNew("glog:", 0)
+	defer g.Close()
+	g.SetFlags(0)
+	g.SetOutput(w)
+	g.SetDedup(time.Hour)
+	for i := 0; i < 3; i++ {
+		g.Info.Println("tick")
+		g.Error.Println("oops")
+	}
+	g.Error.Fatal("fatal")
+	want := "glog:INFO: tick\nglog:ERROR: oops\nglog:ERROR: last message repeated 2 times\n" +
+		"glog:ERROR: fatal\nglog:INFO: last message repeated 2 times\n"
+	if got != want {
+		t.Errorf("Fatal dedup got:%q want:%q", got, want)
+	}
+}
+
 func Test_newllpanic(t *testing.T) {
 	if !testing.Verbose() {
 		log.SetOutput(ioutil.Discard) // toss log.Panic output
@@ -175,3 +251,46 @@ func Test_groupsetoutput(t *testing.T) {
 		}
 	}
 }
+
+func Test_fmtFields(t *testing.T) {
+	tests := []struct {
+		kv   []interface{}
+		want string
+	}{
+		{[]interface{}{"a", 1}, "a=1"},
+		{[]interface{}{"a", 1, "b", "x y"}, `a=1 b="x y"`},
+		{[]interface{}{"a", "k=v", "b", `q"`}, `a="k=v" b="q\""`},
+		{[]interface{}{"a", ""}, `a=""`},
+		{[]interface{}{"a", 1, "odd"}, "a=1 !BADKEY=odd"},
+		{[]interface{}{"a", "\t"}, `a="\t"`},
+	}
+	for _, test := range tests {
+		got := fmtFields(test.kv)
+		if got != test.want {
+			t.Errorf("fmtFields(%v): got:%q want:%q\n", test.kv, got, test.want)
+		}
+	}
+	if got, want := appendFields("msg\n", []interface{}{"a", 1}), "msg a=1\n"; got != want {
+		t.Errorf("appendFields: got:%q want:%q\n", got, want)
+	}
+}
+
+func Test_funcCol(t *testing.T) {
+	tests := []struct {
+		fname string
+		width int
+		want  string
+	}{
+		{"main.run", 0, "FN:main.run() "},
+		{"main.run", 20, "FN:main.run()       "},
+		{"main.run", 14, "FN:main.run() "},
+		{"main.run", 12, "FN:~n.run() "},
+		{"main.run", 5, "FN:main.run() "},
+	}
+	for _, test := range tests {
+		got := funcCol(test.fname, test.width)
+		if got != test.want {
+			t.Errorf("funcCol(%q,%d): got:%q want:%q\n", test.fname, test.width, got, test.want)
+		}
+	}
+}